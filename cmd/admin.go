@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// caRequestSpec is the spec-file form of a CA certificate request.
+// Spec files may be written in YAML or JSON.
+type caRequestSpec struct {
+	Parent             string `json:"parent" yaml:"parent"`
+	AllowSubCa         bool   `json:"allowSubCa" yaml:"allowSubCa"`
+	CommonName         string `json:"commonName" yaml:"commonName"`
+	Country            string `json:"country" yaml:"country"`
+	Province           string `json:"province" yaml:"province"`
+	City               string `json:"city" yaml:"city"`
+	Organization       string `json:"organization" yaml:"organization"`
+	OrganizationalUnit string `json:"organizationalUnit" yaml:"organizationalUnit"`
+	Algorithm          string `json:"algorithm" yaml:"algorithm"`
+	KeySize            int    `json:"keySize" yaml:"keySize"`
+	Expiry             int    `json:"expiry" yaml:"expiry"`
+	Comment            string `json:"comment" yaml:"comment"`
+}
+
+// toRequest validates the spec and converts it to the API request.
+// Defaults match the TUI CA request form.
+func (s *caRequestSpec) toRequest() (api.RequestCACertRequest, error) {
+	if s.CommonName == "" {
+		return api.RequestCACertRequest{}, fmt.Errorf("common name is required")
+	}
+	req := api.RequestCACertRequest{
+		CaUUID:             s.Parent,
+		AllowSubCa:         s.AllowSubCa,
+		Algorithm:          strings.ToUpper(s.Algorithm),
+		KeySize:            s.KeySize,
+		Country:            s.Country,
+		Province:           s.Province,
+		City:               s.City,
+		Organization:       s.Organization,
+		OrganizationalUnit: s.OrganizationalUnit,
+		CommonName:         s.CommonName,
+		Expiry:             s.Expiry,
+		Comment:            s.Comment,
+	}
+	if req.Algorithm == "" {
+		req.Algorithm = "RSA"
+	}
+	if req.KeySize == 0 {
+		req.KeySize = defaultKeySize(req.Algorithm)
+	}
+	if req.Expiry == 0 {
+		req.Expiry = 3650
+	}
+	return req, nil
+}
+
+var (
+	adminCASpecFile string
+	adminCASpec     caRequestSpec
+	adminCAExpiry   int
+	adminCABulk     bulkFlags
+
+	adminCAImportCert    string
+	adminCAImportKey     string
+	adminCAImportComment string
+
+	adminCAAvailable string
+
+	adminCAKeyFormat   string
+	adminCAKeyFile     string
+	adminCAKeyPassword = passwordFlags{env: "CERTVAULT_PASSWORD"}
+)
+
+// adminCmd groups the commands that require the Admin role.
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Administration commands (Admin role+)",
+}
+
+// adminCACmd groups the CA lifecycle commands.
+var adminCACmd = &cobra.Command{
+	Use:   "ca",
+	Short: "Create, renew, import and manage CA certificates",
+}
+
+// adminCACreateCmd creates a root or intermediate CA.
+var adminCACreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a new CA certificate",
+	Long: `Create a new CA certificate from flags or a YAML/JSON spec file.
+
+Flags given on the command line override values from the spec file.
+Without a parent the new CA is a root CA.
+
+Example spec file:
+
+  parent: 1f0e...        # omit for a root CA
+  allowSubCa: true
+  commonName: Acme Intermediate CA
+  organization: Acme Corp
+  algorithm: EC
+  keySize: 384
+  expiry: 1825
+  comment: production intermediate`,
+	Example: `  cvx admin ca create -F intermediate.yaml
+  cvx admin ca create --cn "Acme Root CA" --allow-sub-ca --expiry 7300`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec := &caRequestSpec{}
+		if adminCASpecFile != "" {
+			if err := loadSpec(adminCASpecFile, spec); err != nil {
+				return err
+			}
+		}
+		mergeCARequestFlags(cmd, spec)
+		req, err := spec.toRequest()
+		if err != nil {
+			return err
+		}
+		ca, err := client.RequestAdminCA(context.Background(), req)
+		if err != nil {
+			return checkAuth(err)
+		}
+		clearCompletionCache()
+		return printOutput(ca)
+	},
+}
+
+// mergeCARequestFlags copies explicitly set flags over the spec values.
+func mergeCARequestFlags(cmd *cobra.Command, spec *caRequestSpec) {
+	flags := cmd.Flags()
+	set := func(name string, dst *string, v string) {
+		if flags.Changed(name) {
+			*dst = v
+		}
+	}
+	set("parent", &spec.Parent, adminCASpec.Parent)
+	set("cn", &spec.CommonName, adminCASpec.CommonName)
+	set("country", &spec.Country, adminCASpec.Country)
+	set("province", &spec.Province, adminCASpec.Province)
+	set("city", &spec.City, adminCASpec.City)
+	set("org", &spec.Organization, adminCASpec.Organization)
+	set("ou", &spec.OrganizationalUnit, adminCASpec.OrganizationalUnit)
+	set("algorithm", &spec.Algorithm, adminCASpec.Algorithm)
+	set("comment", &spec.Comment, adminCASpec.Comment)
+	if flags.Changed("allow-sub-ca") {
+		spec.AllowSubCa = adminCASpec.AllowSubCa
+	}
+	if flags.Changed("key-size") {
+		spec.KeySize = adminCASpec.KeySize
+	}
+	if flags.Changed("expiry") {
+		spec.Expiry = adminCASpec.Expiry
+	}
+}
+
+// adminCARenewCmd extends the validity of a CA.
+var adminCARenewCmd = &cobra.Command{
+	Use:   "renew <uuid>",
+	Short: "Renew a CA certificate",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ca, err := client.RenewAdminCA(context.Background(), args[0], api.RenewCACertRequest{Expiry: adminCAExpiry})
+		if err != nil {
+			return checkAuth(err)
+		}
+		clearCompletionCache()
+		return printOutput(ca)
+	},
+}
+
+// adminCAImportCmd imports an existing CA certificate and key from disk.
+var adminCAImportCmd = &cobra.Command{
+	Use:     "import --cert <file> --key <file>",
+	Short:   "Import an existing CA certificate and private key",
+	Example: `  cvx admin ca import --cert ca.pem --key ca-key.pem --comment "legacy root"`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		certPEM, err := os.ReadFile(adminCAImportCert)
+		if err != nil {
+			return err
+		}
+		keyPEM, err := os.ReadFile(adminCAImportKey)
+		if err != nil {
+			return err
+		}
+		// Like the analyze endpoints, the API expects base64-encoded PEM.
+		ca, err := client.ImportAdminCA(context.Background(), api.ImportCACertRequest{
+			Certificate: base64.StdEncoding.EncodeToString(certPEM),
+			PrivKey:     base64.StdEncoding.EncodeToString(keyPEM),
+			Comment:     adminCAImportComment,
+		})
+		if err != nil {
+			return checkAuth(err)
+		}
+		clearCompletionCache()
+		return printOutput(ca)
+	},
+}
+
+// adminCAToggleCmd enables or disables a CA for issuing.
+var adminCAToggleCmd = &cobra.Command{
+	Use:   "toggle <uuid>",
+	Short: "Enable or disable a CA",
+	Long: `Enable or disable a CA for issuing certificates.
+
+Without --available the current state is flipped.`,
+	Example: `  cvx admin ca toggle 1f0e...
+  cvx admin ca toggle 1f0e... --available=false`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		var available bool
+		switch adminCAAvailable {
+		case "true":
+			available = true
+		case "false":
+			available = false
+		case "":
+			ca, err := findCA(ctx, args[0], true)
+			if err != nil {
+				return err
+			}
+			available = !ca.Available
+		default:
+			return fmt.Errorf("--available must be true or false")
+		}
+		if err := client.ToggleAdminCAAvailable(ctx, args[0], available); err != nil {
+			return checkAuth(err)
+		}
+		clearCompletionCache()
+		state := "disabled"
+		if available {
+			state = "enabled"
+		}
+		return printResult(fmt.Sprintf("✓ CA %s %s", args[0], state),
+			result{Action: "toggle", Item: args[0], Status: statusOK, Detail: state})
+	},
+}
+
+// adminCACommentCmd updates a CA comment.
+var adminCACommentCmd = &cobra.Command{
+	Use:   "comment <uuid> <text>",
+	Short: "Set the comment of a CA",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := client.UpdateAdminCAComment(context.Background(), args[0], args[1]); err != nil {
+			return checkAuth(err)
+		}
+		clearCompletionCache()
+		return printResult(fmt.Sprintf("✓ Comment of CA %s updated", args[0]),
+			result{Action: "comment", Item: args[0], Status: statusOK, Detail: args[1]})
+	},
+}
+
+// adminCADeleteCmd deletes one or more CAs.
+var adminCADeleteCmd = &cobra.Command{
+	Use:   "delete <uuid>...",
+	Short: "Delete CA certificates",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		items := make([]bulkItem, len(args))
+		for i, uuid := range args {
+			items[i] = bulkItem{id: uuid}
+		}
+		return adminCABulk.run(context.Background(), "delete", items, client.DeleteAdminCA)
+	},
+}
+
+// adminCAPrivKeyCmd exports the private key of a CA.
+var adminCAPrivKeyCmd = &cobra.Command{
+	Use:   "privkey <uuid>",
+	Short: "Export the private key of a CA",
+	Long: `Export the private key of a CA to a file or stdout.
+
+Decrypting the key requires your login password, read from
+--password-stdin, --password-fd, --password-file, $CERTVAULT_PASSWORD
+or an interactive prompt. Files are written with mode 0600.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		password, err := adminCAKeyPassword.read("Login password: ")
+		if err != nil {
+			return err
+		}
+		encoded, err := client.GetAdminCAPrivKey(context.Background(), args[0], password)
+		if err != nil {
+			return checkAuth(err)
+		}
+		data, err := decodeMaterial(encoded, adminCAKeyFormat)
+		if err != nil {
+			return err
+		}
+		return writeOutput(adminCAKeyFile, data)
+	},
+}
+
+func init() {
+	f := adminCACreateCmd.Flags()
+	f.StringVarP(&adminCASpecFile, "spec", "F", "", "YAML or JSON spec file")
+	f.StringVar(&adminCASpec.Parent, "parent", "", "UUID of the parent CA (omit for a root CA)")
+	f.BoolVar(&adminCASpec.AllowSubCa, "allow-sub-ca", false, "Allow this CA to sign other CAs")
+	f.StringVar(&adminCASpec.CommonName, "cn", "", "Common name")
+	f.StringVar(&adminCASpec.Country, "country", "", "Country code (e.g. US)")
+	f.StringVar(&adminCASpec.Province, "province", "", "State or province")
+	f.StringVar(&adminCASpec.City, "city", "", "City")
+	f.StringVar(&adminCASpec.Organization, "org", "", "Organization")
+	f.StringVar(&adminCASpec.OrganizationalUnit, "ou", "", "Organizational unit")
+	f.StringVar(&adminCASpec.Algorithm, "algorithm", "", "Key algorithm: RSA, EC or ED25519 (default RSA)")
+	f.IntVar(&adminCASpec.KeySize, "key-size", 0, "Key size (default 2048 for RSA, 256 for EC)")
+	f.IntVar(&adminCASpec.Expiry, "expiry", 0, "Validity in days (default 3650)")
+	f.StringVar(&adminCASpec.Comment, "comment", "", "Comment shown in CA lists")
+
+	adminCARenewCmd.Flags().IntVar(&adminCAExpiry, "expiry", 3650, "New validity in days")
+
+	adminCAImportCmd.Flags().StringVar(&adminCAImportCert, "cert", "", "PEM file with the CA certificate")
+	adminCAImportCmd.Flags().StringVar(&adminCAImportKey, "key", "", "PEM file with the CA private key")
+	adminCAImportCmd.Flags().StringVar(&adminCAImportComment, "comment", "", "Comment shown in CA lists")
+	_ = adminCAImportCmd.MarkFlagRequired("cert")
+	_ = adminCAImportCmd.MarkFlagRequired("key")
+
+	adminCAToggleCmd.Flags().StringVar(&adminCAAvailable, "available", "", "Set availability explicitly (true or false)")
+
+	adminCABulk.register(adminCADeleteCmd.Flags())
+
+	adminCAPrivKeyCmd.Flags().StringVar(&adminCAKeyFormat, "format", formatPEM, "Encoding: pem or der")
+	adminCAPrivKeyCmd.Flags().StringVarP(&adminCAKeyFile, "file", "f", "-", "Output file (- for stdout)")
+	adminCAKeyPassword.register(adminCAPrivKeyCmd.Flags(), "", "login password used to decrypt the private key")
+
+	_ = adminCACreateCmd.RegisterFlagCompletionFunc("parent", completeFlag(adminCACompletion))
+	for _, c := range []*cobra.Command{adminCARenewCmd, adminCAToggleCmd, adminCACommentCmd, adminCAPrivKeyCmd} {
+		c.ValidArgsFunction = complete(adminCACompletion, 0)
+	}
+	adminCADeleteCmd.ValidArgsFunction = complete(adminCACompletion)
+
+	adminCACmd.AddCommand(adminCACreateCmd)
+	adminCACmd.AddCommand(adminCARenewCmd)
+	adminCACmd.AddCommand(adminCAImportCmd)
+	adminCACmd.AddCommand(adminCAToggleCmd)
+	adminCACmd.AddCommand(adminCACommentCmd)
+	adminCACmd.AddCommand(adminCADeleteCmd)
+	adminCACmd.AddCommand(adminCAPrivKeyCmd)
+	adminCmd.AddCommand(adminCACmd)
+	rootCmd.AddCommand(adminCmd)
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	adminBindFile   string
+	adminBindBulk   bulkFlags
+	adminUnbindBulk bulkFlags
+	adminMembersNot bool
+)
+
+// adminCABindCmd binds users to a CA so they can request certificates from it.
+var adminCABindCmd = &cobra.Command{
+	Use:   "bind <ca-uuid> [username...]",
+	Short: "Bind users to a CA",
+	Long: `Bind users to a CA so they can request certificates from it.
+
+Usernames are taken from the arguments and from --file (one per line,
+"-" reads stdin; blank lines and # comments are ignored).`,
+	Example: `  cvx admin ca bind 1f0e... alice bob
+  cvx admin ca bind 1f0e... --file team.txt
+  ldap-export | cvx admin ca bind 1f0e... --file -`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		caUUID := args[0]
+		names, err := readNames(args[1:], adminBindFile)
+		if err != nil {
+			return err
+		}
+		// Binding grants access but removes nothing, so no confirmation is asked.
+		adminBindBulk.yes = true
+		return adminBindBulk.run(context.Background(), "bind", nameItems(names), func(ctx context.Context, username string) error {
+			return client.BindUsersToCA(ctx, caUUID, []string{username})
+		})
+	},
+}
+
+// adminCAUnbindCmd removes users from a CA.
+var adminCAUnbindCmd = &cobra.Command{
+	Use:   "unbind <ca-uuid> [username...]",
+	Short: "Unbind users from a CA",
+	Long: `Unbind users from a CA.
+
+Usernames are taken from the arguments and from --file (one per line,
+"-" reads stdin; blank lines and # comments are ignored).`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		caUUID := args[0]
+		names, err := readNames(args[1:], adminBindFile)
+		if err != nil {
+			return err
+		}
+		return adminUnbindBulk.run(context.Background(), "unbind", nameItems(names), func(ctx context.Context, username string) error {
+			return client.UnbindUsersFromCA(ctx, caUUID, []string{username})
+		})
+	},
+}
+
+// adminCAMembersCmd lists the users bound (or not bound) to a CA.
+var adminCAMembersCmd = &cobra.Command{
+	Use:   "members <ca-uuid>",
+	Short: "List users bound to a CA",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		caUUID := args[0]
+		list := client.GetBoundUsers
+		if adminMembersNot {
+			list = client.GetUnboundUsers
+		}
+		users, err := listAll(context.Background(), func(ctx context.Context, page, size int) (*api.PageDTO[api.AdminUser], error) {
+			return list(ctx, caUUID, page, size)
+		})
+		if err != nil {
+			return checkAuth(err)
+		}
+		if users == nil {
+			users = []api.AdminUser{}
+		}
+		return printOutput(users)
+	},
+}
+
+// nameItems wraps plain names as bulk items.
+func nameItems(names []string) []bulkItem {
+	items := make([]bulkItem, len(names))
+	for i, n := range names {
+		items[i] = bulkItem{id: n}
+	}
+	return items
+}
+
+func init() {
+	for _, c := range []*cobra.Command{adminCABindCmd, adminCAUnbindCmd} {
+		c.Flags().StringVar(&adminBindFile, "file", "", `File with one username per line ("-" for stdin)`)
+	}
+	adminBindBulk.registerDryRun(adminCABindCmd.Flags())
+	adminUnbindBulk.register(adminCAUnbindCmd.Flags())
+	adminCAMembersCmd.Flags().BoolVar(&adminMembersNot, "unbound", false, "List users not bound to the CA instead")
+
+	// The first argument is the CA, the rest are usernames.
+	completeBinding := func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return complete(adminCACompletion)(cmd, args, toComplete)
+		}
+		return complete(userCompletion)(cmd, args[1:], toComplete)
+	}
+	adminCABindCmd.ValidArgsFunction = completeBinding
+	adminCAUnbindCmd.ValidArgsFunction = completeBinding
+	adminCAMembersCmd.ValidArgsFunction = complete(adminCACompletion, 0)
+
+	adminCACmd.AddCommand(adminCABindCmd)
+	adminCACmd.AddCommand(adminCAUnbindCmd)
+	adminCACmd.AddCommand(adminCAMembersCmd)
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	loginUsername string
+	loginPassword = passwordFlags{env: "CERTVAULT_PASSWORD"}
+	logoutAll     bool
+)
+
+// loginCmd authenticates without the TUI and saves the session to the config file.
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log in and save the session",
+	Long: `Log in to the CertVault server and save the session cookie to the config file.
+With --server pointing at another server than server_url in the config file,
+the session is only kept for this run.
+
+The password is read from the first available source:
+  --password-stdin, --password-file, $CERTVAULT_PASSWORD,
+  or an interactive prompt when stdin is a terminal.`,
+	Example: `  cvx login -u alice
+  echo "$PASS" | cvx login -u alice --password-stdin
+  CERTVAULT_PASSWORD=secret cvx login -u alice`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		password, err := loginPassword.read("Password: ")
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		if err := client.Login(ctx, loginUsername, password); err != nil {
+			return fmt.Errorf("login failed: %w", err)
+		}
+		profile, err := client.GetProfile(ctx)
+		if err != nil {
+			return fmt.Errorf("login failed: %w", err)
+		}
+		if err := cfg.SetSession(client.GetSession()); err != nil {
+			return fmt.Errorf("save session: %w", err)
+		}
+		if textOutput() {
+			fmt.Printf("✓ Logged in to %s as %s (%s)\n", cfg.ServerURL, profile.Username, api.RoleName(profile.Role))
+			return nil
+		}
+		return printOutput(whoami{UserProfile: *profile, Server: cfg.ServerURL})
+	},
+}
+
+// logoutCmd ends the current session (or every session) and clears it from the config file.
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Log out and clear the saved session",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		var err error
+		if logoutAll {
+			err = client.LogoutAllSessions(ctx)
+		} else {
+			err = client.Logout(ctx)
+		}
+		// An expired session is as good as logged out; still clear it locally.
+		if err != nil && !errors.Is(err, api.ErrUnauthorized) {
+			return fmt.Errorf("logout failed: %w", err)
+		}
+		if err := cfg.SetSession(""); err != nil {
+			return fmt.Errorf("clear session: %w", err)
+		}
+		if logoutAll {
+			return printResult("✓ Logged out of all sessions", result{Action: "logout-all", Item: cfg.ServerURL, Status: statusOK})
+		}
+		return printResult("✓ Logged out", result{Action: "logout", Item: cfg.ServerURL, Status: statusOK})
+	},
+}
+
+// whoamiCmd prints the profile of the logged-in user.
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the currently logged-in user",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, err := client.GetProfile(context.Background())
+		if err != nil {
+			return checkAuth(err)
+		}
+		return printOutput(whoami{UserProfile: *profile, Server: cfg.ServerURL})
+	},
+}
+
+// whoami is the output of whoamiCmd: the profile and the server it is on.
+type whoami struct {
+	api.UserProfile
+	Server string `json:"server"`
+}
+
+func init() {
+	output.Register(
+		output.Column[whoami]{Header: "USERNAME", Value: func(w whoami) string { return w.Username }},
+		output.Column[whoami]{Header: "DISPLAY NAME", Value: func(w whoami) string { return w.DisplayName }},
+		output.Column[whoami]{Header: "EMAIL", Value: func(w whoami) string { return w.Email }},
+		output.Column[whoami]{Header: "ROLE", Value: func(w whoami) string { return api.RoleName(w.Role) }},
+		output.Column[whoami]{Header: "SERVER", Value: func(w whoami) string { return w.Server }},
+	)
+
+	loginCmd.Flags().StringVarP(&loginUsername, "username", "u", "", "CertVault username")
+	_ = loginCmd.MarkFlagRequired("username")
+	loginPassword.register(loginCmd.Flags(), "", "password")
+	logoutCmd.Flags().BoolVar(&logoutAll, "all", false, "Log out every session of the current user")
+
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
+	rootCmd.AddCommand(whoamiCmd)
+}
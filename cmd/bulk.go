@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/pflag"
+)
+
+// bulkItem is one target of a bulk operation.
+type bulkItem struct {
+	id    string
+	label string // optional human-readable description
+}
+
+func (i bulkItem) String() string {
+	if i.label == "" {
+		return i.id
+	}
+	return i.id + " (" + i.label + ")"
+}
+
+// bulkFlags holds the --dry-run and --yes flags shared by bulk commands.
+type bulkFlags struct {
+	dryRun bool
+	yes    bool
+}
+
+// register adds the bulk flags to fs.
+func (b *bulkFlags) register(fs *pflag.FlagSet) {
+	b.registerDryRun(fs)
+	fs.BoolVarP(&b.yes, "yes", "y", false, "Do not ask for confirmation")
+}
+
+// registerDryRun adds only --dry-run, for non-destructive operations that
+// do not need confirmation. Callers must set yes themselves.
+func (b *bulkFlags) registerDryRun(fs *pflag.FlagSet) {
+	fs.BoolVar(&b.dryRun, "dry-run", false, "Print what would be done without changing anything")
+}
+
+// errBulkFailed is returned when at least one item of a bulk operation failed.
+var errBulkFailed = errors.New("some operations failed")
+
+// run asks for confirmation and then applies op to every item, printing a
+// per-item result line and a final summary, or the results in the format of
+// --output. action is a verb such as "renew".
+func (b *bulkFlags) run(ctx context.Context, action string, items []bulkItem, op func(ctx context.Context, id string) error) error {
+	if ok, err := b.proceed(action, items); !ok {
+		return err
+	}
+	results := make([]result, len(items))
+	failed := 0
+	for i, item := range items {
+		results[i] = result{Action: action, Item: item.id, Status: statusOK, Detail: item.label}
+		if err := op(ctx, item.id); err != nil {
+			failed++
+			err = checkAuth(err)
+			results[i].Status, results[i].Error = statusFailed, err.Error()
+			if textOutput() {
+				fmt.Printf("✗ %s: %v\n", item, err)
+			}
+			continue
+		}
+		if textOutput() {
+			fmt.Printf("✓ %s\n", item)
+		}
+	}
+	if failed < len(items) {
+		clearCompletionCache()
+	}
+	if textOutput() {
+		fmt.Printf("%d succeeded, %d failed\n", len(items)-failed, failed)
+	} else if err := printOutput(results); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return errBulkFailed
+	}
+	return nil
+}
+
+// proceed handles the empty, --dry-run and confirmation cases shared by all
+// bulk commands. It reports whether the operation should go ahead.
+func (b *bulkFlags) proceed(action string, items []bulkItem) (bool, error) {
+	if len(items) == 0 {
+		if !textOutput() {
+			return false, printOutput([]result{})
+		}
+		fmt.Println("Nothing to " + action + ".")
+		return false, nil
+	}
+	if b.dryRun {
+		if !textOutput() {
+			results := make([]result, len(items))
+			for i, item := range items {
+				results[i] = result{Action: action, Item: item.id, Status: statusDryRun, Detail: item.label}
+			}
+			return false, printOutput(results)
+		}
+		for _, item := range items {
+			fmt.Printf("would %s %s\n", action, item)
+		}
+		fmt.Printf("Dry run: %d item(s) would be affected.\n", len(items))
+		return false, nil
+	}
+	if !b.yes {
+		ok, err := confirm(fmt.Sprintf("%s %d item(s)?", capitalize(action), len(items)))
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, errors.New("aborted")
+		}
+	}
+	return true, nil
+}
+
+// printBatch reports a single request that applied action to every item:
+// text in the table format, else one result of status per item.
+func printBatch(text, action, status string, items []bulkItem) error {
+	if textOutput() {
+		fmt.Println(text)
+		return nil
+	}
+	results := make([]result, len(items))
+	for i, item := range items {
+		results[i] = result{Action: action, Item: item.id, Status: status, Detail: item.label}
+	}
+	return printOutput(results)
+}
+
+// confirm asks a yes/no question on the terminal. Without a terminal it
+// refuses, so scripts must pass --yes explicitly.
+func confirm(question string) (bool, error) {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return false, errors.New("confirmation required: pass --yes to run non-interactively")
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
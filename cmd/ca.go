@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	caAdmin bool
+
+	caExportChain  bool
+	caExportRoot   bool
+	caExportFormat string
+	caExportFile   string
+)
+
+// caCmd groups the CA certificate commands.
+var caCmd = &cobra.Command{
+	Use:   "ca",
+	Short: "List, inspect and export CA certificates",
+	Long: `List, inspect and export CA certificates.
+
+By default the CAs bound to the current user are used. With --admin
+(Admin role or higher) every CA on the server is visible.`,
+}
+
+// caListCmd prints every CA visible to the current user.
+var caListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List CA certificates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cas, err := listCAs(context.Background(), caAdmin)
+		if err != nil {
+			return err
+		}
+		return printOutput(cas)
+	},
+}
+
+// caGetCmd prints a single CA.
+var caGetCmd = &cobra.Command{
+	Use:   "get <uuid>",
+	Short: "Show a CA certificate",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ca, err := findCA(context.Background(), args[0], caAdmin)
+		if err != nil {
+			return err
+		}
+		return printOutput(ca)
+	},
+}
+
+// caExportCmd writes a CA certificate or chain to a file or stdout.
+var caExportCmd = &cobra.Command{
+	Use:   "export <uuid>",
+	Short: "Export a CA certificate or chain",
+	Long: `Export a CA certificate to a file or stdout.
+
+By default only the CA certificate itself is written. --chain adds its
+parent CAs and --root additionally includes the root CA.`,
+	Example: `  cvx ca export 1f0e... -f root.pem
+  cvx ca export 2a9c... --root -f bundle.pem
+  cvx ca export 2a9c... --format der -f int.der`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkChainFormat(caExportFormat, caExportChain || caExportRoot); err != nil {
+			return err
+		}
+		ctx := context.Background()
+		get := client.GetUserCACert
+		if caAdmin {
+			get = client.GetAdminCACert
+		}
+		// --root only makes sense for a chain, so it implies --chain.
+		encoded, err := get(ctx, args[0], caExportChain || caExportRoot, caExportRoot)
+		if err != nil {
+			return checkAuth(err)
+		}
+		data, err := decodeMaterial(encoded, caExportFormat)
+		if err != nil {
+			return err
+		}
+		return writeOutput(caExportFile, data)
+	},
+}
+
+// listCAs returns every CA visible to the current user, or every CA on the
+// server when admin is set.
+func listCAs(ctx context.Context, admin bool) ([]api.CACert, error) {
+	list := client.ListUserCAs
+	if admin {
+		list = client.ListAdminCAs
+	}
+	cas, err := listAll(ctx, list)
+	if err != nil {
+		return nil, checkAuth(err)
+	}
+	if cas == nil {
+		cas = []api.CACert{}
+	}
+	return cas, nil
+}
+
+// findCA looks up a CA by UUID; the API has no single-CA endpoint.
+func findCA(ctx context.Context, uuid string, admin bool) (*api.CACert, error) {
+	cas, err := listCAs(ctx, admin)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cas {
+		if cas[i].UUID == uuid {
+			return &cas[i], nil
+		}
+	}
+	return nil, fmt.Errorf("CA %s: %w", uuid, api.ErrNotFound)
+}
+
+func init() {
+	caCmd.PersistentFlags().BoolVar(&caAdmin, "admin", false, "Use the admin endpoints to see every CA (Admin role+)")
+	caExportCmd.Flags().BoolVar(&caExportChain, "chain", false, "Include the parent CA chain (without the root CA)")
+	caExportCmd.Flags().BoolVar(&caExportRoot, "root", false, "Include the full chain with the root CA (implies --chain)")
+	caExportCmd.Flags().StringVar(&caExportFormat, "format", formatPEM, "Encoding: pem or der (der holds one certificate, so not with --chain or --root)")
+	caExportCmd.Flags().StringVarP(&caExportFile, "file", "f", "-", "Output file (- for stdout)")
+
+	// --admin switches which CAs are visible, so completion follows it.
+	completeCA := func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if caAdmin {
+			return complete(adminCACompletion, 0)(cmd, args, toComplete)
+		}
+		return complete(caCompletion, 0)(cmd, args, toComplete)
+	}
+	caGetCmd.ValidArgsFunction = completeCA
+	caExportCmd.ValidArgsFunction = completeCA
+
+	caCmd.AddCommand(caListCmd)
+	caCmd.AddCommand(caGetCmd)
+	caCmd.AddCommand(caExportCmd)
+	rootCmd.AddCommand(caCmd)
+}
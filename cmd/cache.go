@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api/cache"
+	"github.com/spf13/cobra"
+)
+
+// responseCache answers reads from disk while the server is unreachable; it
+// is nil when the cache is disabled in the config.
+var responseCache *cache.Service
+
+// newResponseCache wraps next in the cache configured in cfg.
+func newResponseCache(next api.Service) *cache.Service {
+	var ttl time.Duration
+	if cfg.Cache.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(cfg.Cache.TTL); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: invalid cache ttl %q in config: %v\n", cfg.Cache.TTL, err)
+		}
+	}
+	return cache.New(next, cache.DefaultDir(), ttl)
+}
+
+var offlineOnce sync.Once
+
+// warnOffline tells the user, once per run, that the output is cached data.
+func warnOffline(saved time.Time) {
+	offlineOnce.Do(func() {
+		fmt.Fprintf(os.Stderr, "Warning: %s is unreachable; showing cached data from %s (offline, read-only)\n",
+			cfg.ServerURL, saved.Local().Format(time.DateTime))
+	})
+}
+
+// cacheCmd groups the response cache commands.
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the offline response cache",
+}
+
+// cacheClearCmd removes every cached response.
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached responses",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := os.RemoveAll(cache.DefaultDir()); err != nil {
+			return err
+		}
+		fmt.Println("✓ Cache cleared")
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// capabilityAnnotation is the cobra annotation naming the server capability
+// a command, or every command of a group, needs.
+const capabilityAnnotation = "capability"
+
+// requires marks cmds as needing c. Before they run, the server is probed for
+// c so that an older server fails with an explanation instead of an obscure
+// error halfway through.
+func requires(c api.Capability, cmds ...*cobra.Command) {
+	for _, cmd := range cmds {
+		if cmd.Annotations == nil {
+			cmd.Annotations = map[string]string{}
+		}
+		cmd.Annotations[capabilityAnnotation] = c.String()
+	}
+}
+
+// checkCapability probes the server for the capability cmd or one of its
+// parents requires.
+func checkCapability(cmd *cobra.Command) error {
+	for c := cmd; c != nil; c = c.Parent() {
+		if name, ok := c.Annotations[capabilityAnnotation]; ok {
+			want := api.Capability(name)
+			return client.Probe(context.Background(), want).Check(want)
+		}
+	}
+	return nil
+}
+
+func init() {
+	requires(api.CapSessions, sessionListCmd, sessionRevokeCmd, saSessionListCmd)
+	requires(api.CapCABindings, adminCAMembersCmd)
+	requires(api.CapTools, toolsCmd)
+}
@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// certFilter selects SSL certificates by CA, owner, comment and expiry.
+type certFilter struct {
+	caUUID  string
+	owner   string
+	comment string
+	within  string
+}
+
+// register adds the filter flags to fs.
+func (f *certFilter) register(fs *pflag.FlagSet) {
+	fs.StringVar(&f.caUUID, "ca", "", "Only certificates issued by this CA UUID")
+	fs.StringVar(&f.owner, "owner", "", "Only certificates owned by this user")
+	fs.StringVar(&f.comment, "comment", "", "Only certificates whose comment contains this text (case-insensitive)")
+	fs.StringVar(&f.within, "expiring-within", "", "Only certificates expiring within this window, including expired ones (e.g. 30d, 2w, 12h)")
+}
+
+// active reports whether any filter flag was given.
+func (f *certFilter) active() bool {
+	return f.caUUID != "" || f.owner != "" || f.comment != "" || f.within != ""
+}
+
+// apply returns the certificates in certs that match every configured filter.
+func (f *certFilter) apply(certs []api.SSLCert) ([]api.SSLCert, error) {
+	var window time.Duration
+	if f.within != "" {
+		d, err := parseWithin(f.within)
+		if err != nil {
+			return nil, err
+		}
+		window = d
+	}
+	comment := strings.ToLower(f.comment)
+	var out []api.SSLCert
+	for _, c := range certs {
+		if f.caUUID != "" && c.CaUUID != f.caUUID {
+			continue
+		}
+		if f.owner != "" && c.Owner != f.owner {
+			continue
+		}
+		if comment != "" && !strings.Contains(strings.ToLower(c.Comment), comment) {
+			continue
+		}
+		if f.within != "" && !expiresWithin(c.NotAfter, window) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+var (
+	certListFilter certFilter
+
+	certGetChain    bool
+	certGetRoot     bool
+	certGetKey      bool
+	certGetFormat   string
+	certGetFile     string
+	certGetPassword = passwordFlags{env: "CERTVAULT_PASSWORD"}
+
+	certBulkFilter  certFilter
+	certBulk        bulkFlags
+	certRenewExpiry int
+	certCommentText string
+)
+
+// certCmd groups the SSL certificate scripting commands.
+var certCmd = &cobra.Command{
+	Use:   "cert",
+	Short: "Manage SSL certificates",
+}
+
+// certListCmd prints every SSL certificate of the current user.
+var certListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List SSL certificates",
+	Long: `List every SSL certificate of the current user, following all result pages.
+
+Filters can be combined; only certificates matching all of them are printed.`,
+	Example: `  cvx cert list
+  cvx cert list --expiring-within 30d -o csv
+  cvx cert list --ca 1f0e... --comment web -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		certs, err := listAll(context.Background(), client.ListUserSSLCerts)
+		if err != nil {
+			return checkAuth(err)
+		}
+		certs, err = certListFilter.apply(certs)
+		if err != nil {
+			return err
+		}
+		if certs == nil {
+			certs = []api.SSLCert{}
+		}
+		return printOutput(certs)
+	},
+}
+
+// certGetCmd exports a certificate, its chain or its private key.
+var certGetCmd = &cobra.Command{
+	Use:   "get <uuid>",
+	Short: "Export an SSL certificate, chain or private key",
+	Long: `Export an SSL certificate to a file or stdout.
+
+By default only the certificate itself is written. --chain adds the
+intermediate CAs and --root additionally includes the root CA.
+
+With --key the private key is exported instead. Decrypting it requires
+your login password, read from --password-stdin, --password-fd,
+--password-file, $CERTVAULT_PASSWORD or an interactive prompt.
+
+Files are written with mode 0600.`,
+	Example: `  cvx cert get 1f0e... --chain --root --file fullchain.pem
+  cvx cert get 1f0e... --format der --file cert.der
+  cvx cert get 1f0e... --key --password-fd 3 --file key.pem 3<pw.txt`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkChainFormat(certGetFormat, !certGetKey && (certGetChain || certGetRoot)); err != nil {
+			return err
+		}
+		ctx := context.Background()
+		uuid := args[0]
+		var encoded string
+		var err error
+		if certGetKey {
+			password, perr := certGetPassword.read("Login password: ")
+			if perr != nil {
+				return perr
+			}
+			encoded, err = client.GetUserSSLPrivKey(ctx, uuid, password)
+		} else {
+			// --root only makes sense for a chain, so it implies --chain.
+			encoded, err = client.GetUserSSLCert(ctx, uuid, certGetChain || certGetRoot, certGetRoot)
+		}
+		if err != nil {
+			return checkAuth(err)
+		}
+		data, err := decodeMaterial(encoded, certGetFormat)
+		if err != nil {
+			return err
+		}
+		return writeOutput(certGetFile, data)
+	},
+}
+
+// certRenewCmd renews one or more SSL certificates.
+var certRenewCmd = &cobra.Command{
+	Use:   "renew [uuid...]",
+	Short: "Renew SSL certificates",
+	Long: `Renew the given SSL certificates, or every certificate matched by the
+selector flags (--ca, --owner, --comment, --expiring-within).`,
+	Example: `  cvx cert renew 1f0e... 2a9c... --expiry 365
+  cvx cert renew --expiring-within 14d --ca 1f0e... --dry-run
+  cvx cert renew --expiring-within 14d --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		items, err := selectCerts(ctx, args, &certBulkFilter)
+		if err != nil {
+			return err
+		}
+		return certBulk.run(ctx, "renew", items, func(ctx context.Context, uuid string) error {
+			_, err := client.RenewSSLCert(ctx, uuid, api.RenewSSLCertRequest{Expiry: certRenewExpiry})
+			return err
+		})
+	},
+}
+
+// certDeleteCmd deletes one or more SSL certificates.
+var certDeleteCmd = &cobra.Command{
+	Use:   "delete [uuid...]",
+	Short: "Delete SSL certificates",
+	Long: `Delete the given SSL certificates, or every certificate matched by the
+selector flags (--ca, --owner, --comment, --expiring-within).`,
+	Example: `  cvx cert delete 1f0e...
+  cvx cert delete --expiring-within 0d --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		items, err := selectCerts(ctx, args, &certBulkFilter)
+		if err != nil {
+			return err
+		}
+		return certBulk.run(ctx, "delete", items, client.DeleteSSLCert)
+	},
+}
+
+// certCommentCmd sets the comment of one or more SSL certificates.
+var certCommentCmd = &cobra.Command{
+	Use:   "comment --set <text> [uuid...]",
+	Short: "Set the comment of SSL certificates",
+	Example: `  cvx cert comment --set "api gateway" 1f0e...
+  cvx cert comment --set "legacy" --ca 1f0e... --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		items, err := selectCerts(ctx, args, &certBulkFilter)
+		if err != nil {
+			return err
+		}
+		return certBulk.run(ctx, "update", items, func(ctx context.Context, uuid string) error {
+			return client.UpdateSSLCertComment(ctx, uuid, certCommentText)
+		})
+	},
+}
+
+// selectCerts resolves the targets of a bulk command: either the UUIDs given
+// as arguments or every certificate matching the selector flags.
+func selectCerts(ctx context.Context, args []string, filter *certFilter) ([]bulkItem, error) {
+	switch {
+	case len(args) > 0 && filter.active():
+		return nil, fmt.Errorf("pass either certificate UUIDs or selector flags, not both")
+	case len(args) > 0:
+		items := make([]bulkItem, len(args))
+		for i, uuid := range args {
+			items[i] = bulkItem{id: uuid}
+		}
+		return items, nil
+	case !filter.active():
+		return nil, fmt.Errorf("no certificates selected: pass UUIDs or selector flags (--ca, --owner, --comment, --expiring-within)")
+	}
+	certs, err := listAll(ctx, client.ListUserSSLCerts)
+	if err != nil {
+		return nil, checkAuth(err)
+	}
+	certs, err = filter.apply(certs)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]bulkItem, len(certs))
+	for i, c := range certs {
+		items[i] = bulkItem{id: c.UUID, label: c.Comment}
+	}
+	return items, nil
+}
+
+func init() {
+	certListFilter.register(certListCmd.Flags())
+
+	certGetCmd.Flags().BoolVar(&certGetChain, "chain", false, "Include the CA chain (without the root CA)")
+	certGetCmd.Flags().BoolVar(&certGetRoot, "root", false, "Include the full chain with the root CA (implies --chain)")
+	certGetCmd.Flags().BoolVar(&certGetKey, "key", false, "Export the private key instead of the certificate")
+	certGetCmd.Flags().StringVar(&certGetFormat, "format", formatPEM, "Encoding: pem or der (der holds one certificate, so not with --chain or --root)")
+	certGetCmd.Flags().StringVarP(&certGetFile, "file", "f", "-", "Output file (- for stdout)")
+	certGetPassword.register(certGetCmd.Flags(), "", "login password used to decrypt the private key")
+	certGetCmd.MarkFlagsMutuallyExclusive("key", "chain")
+	certGetCmd.MarkFlagsMutuallyExclusive("key", "root")
+
+	for _, c := range []*cobra.Command{certRenewCmd, certDeleteCmd, certCommentCmd} {
+		certBulkFilter.register(c.Flags())
+		certBulk.register(c.Flags())
+	}
+	certRenewCmd.Flags().IntVar(&certRenewExpiry, "expiry", 365, "New validity in days")
+	certCommentCmd.Flags().StringVar(&certCommentText, "set", "", "New comment")
+	_ = certCommentCmd.MarkFlagRequired("set")
+
+	certGetCmd.ValidArgsFunction = complete(certCompletion, 0)
+	for _, c := range []*cobra.Command{certListCmd, certRenewCmd, certDeleteCmd, certCommentCmd} {
+		_ = c.RegisterFlagCompletionFunc("ca", completeFlag(caCompletion))
+	}
+	for _, c := range []*cobra.Command{certRenewCmd, certDeleteCmd, certCommentCmd} {
+		c.ValidArgsFunction = complete(certCompletion)
+	}
+
+	certCmd.AddCommand(certListCmd)
+	certCmd.AddCommand(certGetCmd)
+	certCmd.AddCommand(certRenewCmd)
+	certCmd.AddCommand(certDeleteCmd)
+	certCmd.AddCommand(certCommentCmd)
+	rootCmd.AddCommand(certCmd)
+}
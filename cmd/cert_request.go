@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// certRequestSpec is the spec-file form of an SSL certificate request.
+// Spec files may be written in YAML or JSON.
+type certRequestSpec struct {
+	CA                 string   `json:"ca" yaml:"ca"`
+	CommonName         string   `json:"commonName" yaml:"commonName"`
+	Country            string   `json:"country" yaml:"country"`
+	Province           string   `json:"province" yaml:"province"`
+	City               string   `json:"city" yaml:"city"`
+	Organization       string   `json:"organization" yaml:"organization"`
+	OrganizationalUnit string   `json:"organizationalUnit" yaml:"organizationalUnit"`
+	SANs               []string `json:"sans" yaml:"sans"`
+	Algorithm          string   `json:"algorithm" yaml:"algorithm"`
+	KeySize            int      `json:"keySize" yaml:"keySize"`
+	Expiry             int      `json:"expiry" yaml:"expiry"`
+	Comment            string   `json:"comment" yaml:"comment"`
+}
+
+// sanTypes maps the short SAN prefixes accepted on the command line to API SAN types.
+var sanTypes = map[string]string{
+	"dns":   "DNS_NAME",
+	"ip":    "IP_ADDRESS",
+	"email": "EMAIL",
+	"uri":   "URI",
+}
+
+// parseSAN parses a typed SAN entry such as "dns:example.com" or "ip:10.0.0.1".
+// Entries without a type prefix are treated as DNS names, like the TUI form.
+// Full API type names (e.g. "IP_ADDRESS:10.0.0.1") are accepted as well;
+// IPv6 addresses need an explicit prefix ("ip:::1").
+func parseSAN(s string) (api.SubjectAltName, error) {
+	s = strings.TrimSpace(s)
+	prefix, value, found := strings.Cut(s, ":")
+	if !found {
+		return api.SubjectAltName{Type: sanTypes["dns"], Value: s}, nil
+	}
+	if t, ok := sanTypes[strings.ToLower(prefix)]; ok {
+		return api.SubjectAltName{Type: t, Value: value}, nil
+	}
+	for _, t := range sanTypes {
+		if strings.EqualFold(prefix, t) {
+			return api.SubjectAltName{Type: t, Value: value}, nil
+		}
+	}
+	return api.SubjectAltName{}, fmt.Errorf("unknown SAN type %q in %q (want dns, ip, email or uri)", prefix, s)
+}
+
+// loadSpec decodes a YAML or JSON spec file into v.
+func loadSpec(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	// YAML is a superset of JSON, so one decoder handles both.
+	if err := yaml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// defaultKeySize returns the key size used when none is given, matching the TUI forms.
+func defaultKeySize(algorithm string) int {
+	switch algorithm {
+	case "RSA":
+		return 2048
+	case "EC":
+		return 256
+	}
+	return 0
+}
+
+// toRequest validates the spec and converts it to the API request.
+// Defaults match the TUI request form.
+func (s *certRequestSpec) toRequest() (api.RequestSSLCertRequest, error) {
+	if s.CA == "" || s.CommonName == "" {
+		return api.RequestSSLCertRequest{}, fmt.Errorf("CA and common name are required")
+	}
+	req := api.RequestSSLCertRequest{
+		CaUUID:             s.CA,
+		Algorithm:          strings.ToUpper(s.Algorithm),
+		KeySize:            s.KeySize,
+		Country:            s.Country,
+		Province:           s.Province,
+		City:               s.City,
+		Organization:       s.Organization,
+		OrganizationalUnit: s.OrganizationalUnit,
+		CommonName:         s.CommonName,
+		Expiry:             s.Expiry,
+		Comment:            s.Comment,
+	}
+	if req.Algorithm == "" {
+		req.Algorithm = "RSA"
+	}
+	if req.KeySize == 0 {
+		req.KeySize = defaultKeySize(req.Algorithm)
+	}
+	if req.Expiry == 0 {
+		req.Expiry = 365
+	}
+	for _, entry := range s.SANs {
+		san, err := parseSAN(entry)
+		if err != nil {
+			return api.RequestSSLCertRequest{}, err
+		}
+		req.SubjectAltNames = append(req.SubjectAltNames, san)
+	}
+	return req, nil
+}
+
+var (
+	certReqSpecFile string
+	certReqSpec     certRequestSpec
+	certReqOutDir   string
+	certReqWithKey  bool
+	certReqPassword = passwordFlags{env: "CERTVAULT_PASSWORD"}
+)
+
+// certRequestCmd issues a new SSL certificate from flags or a spec file.
+var certRequestCmd = &cobra.Command{
+	Use:   "request",
+	Short: "Request a new SSL certificate",
+	Long: `Request a new SSL certificate from flags or a YAML/JSON spec file.
+
+Flags given on the command line override values from the spec file.
+SAN entries are typed: dns:example.com, ip:10.0.0.1, email:ops@example.com,
+uri:https://example.com. Entries without a prefix are DNS names.
+
+With --out-dir the new certificate (cert.pem) and its full chain
+(fullchain.pem) are downloaded into the directory; add --with-key to
+also download the private key (key.pem), which needs your login password.
+
+Example spec file:
+
+  ca: 1f0e...
+  commonName: api.example.com
+  organization: Acme Corp
+  sans: [dns:api.example.com, "ip:10.0.0.5"]
+  algorithm: EC
+  keySize: 256
+  expiry: 90
+  comment: api gateway`,
+	Example: `  cvx cert request --ca 1f0e... --cn api.example.com --san dns:api.example.com --san ip:10.0.0.5
+  cvx cert request -F api.yaml --out-dir ./tls --with-key --password-file ~/.cvx-pass`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec := &certRequestSpec{}
+		if certReqSpecFile != "" {
+			if err := loadSpec(certReqSpecFile, spec); err != nil {
+				return err
+			}
+		}
+		mergeCertRequestFlags(cmd, spec)
+		req, err := spec.toRequest()
+		if err != nil {
+			return err
+		}
+		var password string
+		if certReqWithKey {
+			if certReqOutDir == "" {
+				return fmt.Errorf("--with-key requires --out-dir")
+			}
+			// Ask before submitting so a missing password does not leave a half-finished download.
+			if password, err = certReqPassword.read("Login password: "); err != nil {
+				return err
+			}
+		}
+		ctx := context.Background()
+		cert, err := client.RequestSSLCert(ctx, req)
+		if err != nil {
+			return checkAuth(err)
+		}
+		clearCompletionCache()
+		if certReqOutDir != "" {
+			if err := downloadCertBundle(ctx, cert.UUID, certReqOutDir, certReqWithKey, password); err != nil {
+				return fmt.Errorf("certificate %s issued, but download failed: %w", cert.UUID, err)
+			}
+		}
+		return printOutput(cert)
+	},
+}
+
+// mergeCertRequestFlags copies explicitly set flags over the spec values.
+func mergeCertRequestFlags(cmd *cobra.Command, spec *certRequestSpec) {
+	flags := cmd.Flags()
+	set := func(name string, dst *string, v string) {
+		if flags.Changed(name) {
+			*dst = v
+		}
+	}
+	set("ca", &spec.CA, certReqSpec.CA)
+	set("cn", &spec.CommonName, certReqSpec.CommonName)
+	set("country", &spec.Country, certReqSpec.Country)
+	set("province", &spec.Province, certReqSpec.Province)
+	set("city", &spec.City, certReqSpec.City)
+	set("org", &spec.Organization, certReqSpec.Organization)
+	set("ou", &spec.OrganizationalUnit, certReqSpec.OrganizationalUnit)
+	set("algorithm", &spec.Algorithm, certReqSpec.Algorithm)
+	set("comment", &spec.Comment, certReqSpec.Comment)
+	if flags.Changed("key-size") {
+		spec.KeySize = certReqSpec.KeySize
+	}
+	if flags.Changed("expiry") {
+		spec.Expiry = certReqSpec.Expiry
+	}
+	if flags.Changed("san") {
+		spec.SANs = certReqSpec.SANs
+	}
+}
+
+// downloadCertBundle writes cert.pem, fullchain.pem and optionally key.pem into dir.
+func downloadCertBundle(ctx context.Context, uuid, dir string, withKey bool, password string) error {
+	type bundleFile struct {
+		name  string
+		fetch func() (string, error)
+	}
+	files := []bundleFile{
+		{"cert.pem", func() (string, error) { return client.GetUserSSLCert(ctx, uuid, false, false) }},
+		{"fullchain.pem", func() (string, error) { return client.GetUserSSLCert(ctx, uuid, true, true) }},
+	}
+	if withKey {
+		files = append(files, bundleFile{"key.pem", func() (string, error) { return client.GetUserSSLPrivKey(ctx, uuid, password) }})
+	}
+	for _, f := range files {
+		encoded, err := f.fetch()
+		if err != nil {
+			return err
+		}
+		data, err := decodeMaterial(encoded, formatPEM)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, f.name)
+		if err := writeOutput(path, data); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "✓ Saved %s\n", path)
+	}
+	return nil
+}
+
+func init() {
+	f := certRequestCmd.Flags()
+	f.StringVarP(&certReqSpecFile, "spec", "F", "", "YAML or JSON spec file")
+	f.StringVar(&certReqSpec.CA, "ca", "", "UUID of the signing CA")
+	f.StringVar(&certReqSpec.CommonName, "cn", "", "Common name")
+	f.StringVar(&certReqSpec.Country, "country", "", "Country code (e.g. US)")
+	f.StringVar(&certReqSpec.Province, "province", "", "State or province")
+	f.StringVar(&certReqSpec.City, "city", "", "City")
+	f.StringVar(&certReqSpec.Organization, "org", "", "Organization")
+	f.StringVar(&certReqSpec.OrganizationalUnit, "ou", "", "Organizational unit")
+	f.StringArrayVar(&certReqSpec.SANs, "san", nil, "Subject alternative name, e.g. dns:example.com or ip:10.0.0.1 (repeatable)")
+	f.StringVar(&certReqSpec.Algorithm, "algorithm", "", "Key algorithm: RSA, EC or ED25519 (default RSA)")
+	f.IntVar(&certReqSpec.KeySize, "key-size", 0, "Key size: 2048/4096 for RSA, 256/384 for EC (default 2048 for RSA, 256 for EC)")
+	f.IntVar(&certReqSpec.Expiry, "expiry", 0, "Validity in days (default 365)")
+	f.StringVar(&certReqSpec.Comment, "comment", "", "Comment shown in certificate lists")
+	f.StringVar(&certReqOutDir, "out-dir", "", "Download the issued certificate and chain into this directory")
+	f.BoolVar(&certReqWithKey, "with-key", false, "Also download the private key (requires --out-dir)")
+	certReqPassword.register(f, "", "login password used to decrypt the private key")
+	_ = certRequestCmd.RegisterFlagCompletionFunc("ca", completeFlag(caCompletion))
+
+	certCmd.AddCommand(certRequestCmd)
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/config"
+	"github.com/spf13/pflag"
+)
+
+// Connection flags; each overrides the matching config file setting.
+var (
+	retries         int
+	timeout         time.Duration
+	tlsCAFile       string
+	tlsClientCert   string
+	tlsClientKey    string
+	tlsPins         []string
+	tlsInsecureFlag bool
+	proxyFlag       string
+	unixSocket      string
+	headerFlags     []string
+	debugFile       string
+
+	// debugLog is the open --debug log file, closed by Execute.
+	debugLog *os.File
+
+	// insecureTLS records whether the client was built without certificate
+	// verification, so every command can warn about it.
+	insecureTLS bool
+
+	// connFlags holds the flags above, to tell which were given.
+	connFlags *pflag.FlagSet
+)
+
+const insecureWarning = `WARNING: TLS certificate verification is DISABLED (--insecure / "insecure" in config).
+WARNING: Anyone on the network path can impersonate the server and steal your session.
+WARNING: Outside of a lab, trust the server's CA with --ca-file instead, optionally with --pin-sha256 on top.`
+
+// clientOptions builds the API client options from flags, falling back to
+// the config file and then to the api defaults.
+func clientOptions() ([]api.Option, error) {
+	policy := api.DefaultRetryPolicy
+	if cfg.Retries != nil {
+		policy.MaxRetries = *cfg.Retries
+	}
+	if retries >= 0 {
+		policy.MaxRetries = retries
+	}
+	d := timeout
+	if d == 0 && cfg.Timeout != "" {
+		var err error
+		if d, err = time.ParseDuration(cfg.Timeout); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: invalid timeout %q in config: %v\n", cfg.Timeout, err)
+		}
+	}
+	opts := []api.Option{api.WithRetry(policy), api.WithTimeout(d)}
+
+	tlsOpts := tlsOptions()
+	insecureTLS = tlsOpts.Insecure
+	if !tlsOpts.IsZero() {
+		tlsCfg, err := tlsOpts.Config()
+		if err != nil {
+			return nil, fmt.Errorf("TLS configuration: %w", err)
+		}
+		opts = append(opts, api.WithTLSConfig(tlsCfg))
+	}
+
+	proxy := cfg.Proxy
+	if proxyFlag != "" {
+		proxy = proxyFlag
+	}
+	if proxy != "" {
+		u, err := api.ParseProxy(proxy)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, api.WithProxy(u))
+	}
+	socket := cfg.UnixSocket
+	if unixSocket != "" {
+		socket = unixSocket
+	}
+	if socket != "" {
+		opts = append(opts, api.WithUnixSocket(socket))
+	}
+
+	headers := cfg.HeadersFor(cfg.ServerURL)
+	for _, h := range headerFlags {
+		name, value, err := api.ParseHeader(h)
+		if err != nil {
+			return nil, err
+		}
+		headers.Set(name, value)
+	}
+	opts = append(opts, api.WithHeaders(headers))
+	return opts, nil
+}
+
+// debugOption opens the request log of --debug or CVX_DEBUG as debugLog and
+// returns the option tracing to it, or nil when debugging is off.
+func debugOption() (api.Option, error) {
+	path := debugLogPath()
+	if path == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("debug log: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("debug log: %w", err)
+	}
+	debugLog = f
+	return api.WithTrace(api.TraceLogger(f)), nil
+}
+
+// defaultDebugLog is where --debug and CVX_DEBUG=1 write the request log.
+func defaultDebugLog() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, config.ConfigDirName, "debug.log")
+}
+
+// debugLogPath returns the request log file from --debug or CVX_DEBUG, or ""
+// when debugging is off. CVX_DEBUG is either a boolean or a file path.
+func debugLogPath() string {
+	if debugFile != "" {
+		return debugFile
+	}
+	v := os.Getenv("CVX_DEBUG")
+	if on, err := strconv.ParseBool(v); err == nil {
+		if on {
+			return defaultDebugLog()
+		}
+		return ""
+	}
+	return v
+}
+
+// tlsOptions merges the TLS flags over the config file settings.
+func tlsOptions() api.TLSOptions {
+	o := api.TLSOptions{
+		CAFile:    cfg.TLS.CAFile,
+		CertFile:  cfg.TLS.ClientCert,
+		KeyFile:   cfg.TLS.ClientKey,
+		PinSHA256: cfg.TLS.PinSHA256,
+		Insecure:  cfg.TLS.Insecure,
+	}
+	flags := connFlags
+	if flags.Changed("ca-file") {
+		o.CAFile = tlsCAFile
+	}
+	if flags.Changed("client-cert") {
+		o.CertFile = tlsClientCert
+	}
+	if flags.Changed("client-key") {
+		o.KeyFile = tlsClientKey
+	}
+	if flags.Changed("pin-sha256") {
+		o.PinSHA256 = tlsPins
+	}
+	if flags.Changed("insecure") {
+		o.Insecure = tlsInsecureFlag
+	}
+	return o
+}
+
+func init() {
+	f := rootCmd.PersistentFlags()
+	connFlags = f
+	f.IntVar(&retries, "retries", -1, "Retries for failed read requests (default from config, else 3)")
+	f.DurationVar(&timeout, "timeout", 0, "Timeout per request attempt, e.g. 45s (default from config, else 30s)")
+	f.StringVar(&tlsCAFile, "ca-file", "", "PEM bundle of extra CAs to trust for the server certificate")
+	f.StringVar(&tlsClientCert, "client-cert", "", "PEM client certificate for mutual TLS")
+	f.StringVar(&tlsClientKey, "client-key", "", "PEM private key of --client-cert")
+	f.StringSliceVar(&tlsPins, "pin-sha256", nil, "Require the server certificate, or a CA in its verified chain, to have this SHA-256 fingerprint (repeatable); pins add to verification, so use them with --ca-file for a private or self-signed server, while with --insecure only the server certificate counts")
+	f.BoolVar(&tlsInsecureFlag, "insecure", false, "Skip TLS certificate verification (lab use only)")
+	f.StringVar(&proxyFlag, "proxy", "", `Proxy URL (http, https, socks5, socks5h), or "direct" to ignore HTTP(S)_PROXY`)
+	f.StringVar(&unixSocket, "unix-socket", "", "Connect to the server through this Unix domain socket")
+	f.StringVar(&debugFile, "debug", "", "Log every request and response, secrets redacted, to this file (--debug=<file>)")
+	f.Lookup("debug").NoOptDefVal = defaultDebugLog()
+	f.StringArrayVarP(&headerFlags, "header", "H", nil, `Extra request header "Name: value" (repeatable)`)
+}
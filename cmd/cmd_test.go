@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api/fake"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// isolate points the config and cache of cvx to a temporary directory.
+func isolate(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir+"/config")
+	t.Setenv("XDG_CACHE_HOME", dir+"/cache")
+	for _, env := range []string{"CERTVAULT_URL", "CERTVAULT_SESSION", "CERTVAULT_PASSWORD", "CVX_DEBUG"} {
+		t.Setenv(env, "")
+		os.Unsetenv(env)
+	}
+}
+
+// mockServer starts a fake CertVault server with the demo data.
+func mockServer(t *testing.T) (*fake.Store, string) {
+	t.Helper()
+	store := fake.NewStore()
+	if err := store.Seed(); err != nil {
+		t.Fatal(err)
+	}
+	srv := fake.NewServer(store)
+	t.Cleanup(srv.Close)
+	return store, srv.URL
+}
+
+// saveServer writes a config file with server_url set to url.
+func saveServer(t *testing.T, url string) {
+	t.Helper()
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ServerURL = url
+	if err := config.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// loadConfig reads the config file.
+func loadConfig(t *testing.T) *config.Config {
+	t.Helper()
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// run executes cvx with args like main does and returns its standard output.
+// Flags left over from earlier runs are reset first.
+func run(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	resetFlags(rootCmd)
+	rootCmd.SetArgs(args)
+
+	stdout := os.Stdout
+	f, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	os.Stdout = f
+	defer func() { os.Stdout = stdout }()
+
+	runErr := rootCmd.Execute()
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out), runErr
+}
+
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if s, ok := f.Value.(pflag.SliceValue); ok {
+			_ = s.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+func TestSessionOfServerFlagIsSavedApart(t *testing.T) {
+	isolate(t)
+	_, url := mockServer(t)
+	const configured = "https://certvault.example"
+	saveServer(t, configured)
+	t.Setenv("CERTVAULT_PASSWORD", "alice")
+
+	if _, err := run(t, "--server", url, "login", "-u", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg := loadConfig(t); cfg.ServerURL != configured || cfg.Session != "" || cfg.Sessions[url] == "" {
+		t.Errorf("after login: saved server %q, session %q, sessions %v; want %q, no session, one for %s", cfg.ServerURL, cfg.Session, cfg.Sessions, configured, url)
+	}
+	out, err := run(t, "--server", url, "whoami", "-o", "jsonpath={.username}")
+	if err != nil || out != "alice\n" {
+		t.Fatalf("whoami = %q, %v; want alice", out, err)
+	}
+
+	for _, args := range [][]string{
+		{"--server", url, "logout"},
+		{"--server", url, "logout", "--all"},
+	} {
+		if _, err := run(t, args...); err != nil {
+			t.Fatalf("cvx %v: %v", args, err)
+		}
+		cfg := loadConfig(t)
+		if cfg.ServerURL != configured || cfg.Session != "" || len(cfg.Sessions) != 0 {
+			t.Errorf("after cvx %v: saved server %q, session %q, sessions %v; want %q, no sessions", args, cfg.ServerURL, cfg.Session, cfg.Sessions, configured)
+		}
+	}
+}
+
+func TestLoginSavesSessionOfConfiguredServer(t *testing.T) {
+	isolate(t)
+	_, url := mockServer(t)
+	saveServer(t, url)
+	t.Setenv("CERTVAULT_PASSWORD", "alice")
+
+	if _, err := run(t, "login", "-u", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg := loadConfig(t); cfg.ServerURL != url || cfg.Session == "" {
+		t.Fatalf("after login: saved server %q, session %q; want %q and a session", cfg.ServerURL, cfg.Session, url)
+	}
+	out, err := run(t, "whoami", "-o", "jsonpath={.username}")
+	if err != nil || out != "alice\n" {
+		t.Fatalf("whoami = %q, %v; want alice", out, err)
+	}
+	if _, err := run(t, "logout"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg := loadConfig(t); cfg.ServerURL != url || cfg.Session != "" {
+		t.Errorf("after logout: saved server %q, session %q; want %q, no session", cfg.ServerURL, cfg.Session, url)
+	}
+}
+
+func TestRevokeAllWithServerFlagKeepsConfig(t *testing.T) {
+	isolate(t)
+	store, url := mockServer(t)
+	const configured = "https://certvault.example"
+	saveServer(t, configured)
+	alice := store.Client()
+	if err := alice.Login(context.Background(), "alice", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CERTVAULT_SESSION", alice.GetSession())
+
+	if _, err := run(t, "--server", url, "session", "revoke-all", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	os.Unsetenv("CERTVAULT_SESSION")
+	if cfg := loadConfig(t); cfg.ServerURL != configured || cfg.Session != "" {
+		t.Errorf("saved server %q, session %q; want %q, no session", cfg.ServerURL, cfg.Session, configured)
+	}
+	if _, err := alice.GetProfile(context.Background()); !errors.Is(err, api.ErrUnauthorized) {
+		t.Errorf("GetProfile() after revoke-all: %v, want ErrUnauthorized", err)
+	}
+}
+
+// completions runs a completion request for args and returns the offered
+// values, without descriptions.
+func completions(t *testing.T, args ...string) []string {
+	t.Helper()
+	completionOnce, completionErr = sync.Once{}, nil
+	out, err := run(t, append([]string{cobra.ShellCompNoDescRequestCmd}, args...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values []string
+	for _, line := range strings.Split(out, "\n") {
+		if line != "" && !strings.HasPrefix(line, ":") {
+			values = append(values, line)
+		}
+	}
+	return values
+}
+
+func TestCompletionForgetsDeletedCert(t *testing.T) {
+	isolate(t)
+	_, url := mockServer(t)
+	saveServer(t, url)
+	t.Setenv("CERTVAULT_PASSWORD", "alice")
+	if _, err := run(t, "login", "-u", "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	before := completions(t, "cert", "delete", "")
+	if len(before) == 0 {
+		t.Fatal("no certificates offered")
+	}
+	if _, err := run(t, "cert", "delete", before[0], "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	after := completions(t, "cert", "delete", "")
+	if slices.Contains(after, before[0]) || len(after) != len(before)-1 {
+		t.Errorf("after deleting %s completion offers %v", before[0], after)
+	}
+}
+
+func TestCompletionHonorsServerFlag(t *testing.T) {
+	isolate(t)
+	store, url := mockServer(t)
+	saveServer(t, "https://certvault.example")
+	alice := store.Client()
+	if err := alice.Login(context.Background(), "alice", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CERTVAULT_SESSION", alice.GetSession())
+	logFile := t.TempDir() + "/debug.log"
+	t.Setenv("CVX_DEBUG", logFile)
+
+	if got := completions(t, "--server", url, "cert", "get", ""); len(got) == 0 {
+		t.Error("no certificates offered from the --server server")
+	}
+	if _, err := os.Stat(logFile); !os.IsNotExist(err) {
+		t.Errorf("completion opened the debug log: %v", err)
+	}
+}
+
+func TestBulkResultsFollowOutputFormat(t *testing.T) {
+	isolate(t)
+	_, url := mockServer(t)
+	saveServer(t, url)
+	t.Setenv("CERTVAULT_PASSWORD", "alice")
+
+	out, err := run(t, "login", "-u", "alice", "-o", "jsonpath={.username}")
+	if err != nil || out != "alice\n" {
+		t.Fatalf("login = %q, %v; want alice", out, err)
+	}
+	uuids := completions(t, "cert", "delete", "")
+	if len(uuids) == 0 {
+		t.Fatal("no certificates")
+	}
+
+	out, err = run(t, "cert", "delete", uuids[0], "--dry-run", "-o", "jsonpath={[*].status}")
+	if err != nil || out != "dry-run\n" {
+		t.Errorf("dry run = %q, %v; want dry-run", out, err)
+	}
+	out, err = run(t, "cert", "delete", uuids[0], "missing-uuid", "--yes", "-o", "json")
+	if !errors.Is(err, errBulkFailed) {
+		t.Errorf("delete error = %v, want errBulkFailed", err)
+	}
+	var results []result
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("%v in %q", err, out)
+	}
+	if len(results) != 2 || results[0].Status != statusOK || results[1].Status != statusFailed || results[1].Error == "" {
+		t.Errorf("results = %+v", results)
+	}
+	if strings.Contains(out, "✓") {
+		t.Errorf("decorated text in JSON output: %q", out)
+	}
+}
+
+func TestDERExportRejectsChain(t *testing.T) {
+	isolate(t)
+	_, url := mockServer(t)
+	saveServer(t, url)
+	t.Setenv("CERTVAULT_PASSWORD", "alice")
+	if _, err := run(t, "login", "-u", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	uuids := completions(t, "cert", "get", "")
+	if len(uuids) == 0 {
+		t.Fatal("no certificates")
+	}
+
+	for _, args := range [][]string{
+		{"cert", "get", uuids[0], "--format", "der", "--chain"},
+		{"cert", "get", uuids[0], "--format", "der", "--root"},
+		{"ca", "export", "any", "--format", "der", "--root"},
+	} {
+		if _, err := run(t, args...); err == nil || !strings.Contains(err.Error(), "--format der") {
+			t.Errorf("cvx %v: error %v, want a --format der error", args, err)
+		}
+	}
+	out, err := run(t, "cert", "get", uuids[0], "--format", "der")
+	if err != nil || len(out) == 0 || strings.Contains(out, "-----BEGIN") {
+		t.Errorf("cert get --format der = %d bytes, %v; want DER", len(out), err)
+	}
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/config"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// completionTTL is how long fetched candidates are reused. Completion
+	// runs on every <Tab>, so even a short cache saves most round-trips.
+	completionTTL = time.Minute
+	// completionTimeout bounds the request so a slow server cannot hang the shell.
+	completionTimeout = 3 * time.Second
+)
+
+// completionItem is one candidate with the description shown next to it.
+type completionItem struct {
+	Value string `json:"value"`
+	Desc  string `json:"desc,omitempty"`
+}
+
+// completionCache is the on-disk form of cached candidates.
+type completionCache struct {
+	Fetched time.Time        `json:"fetched"`
+	Items   []completionItem `json:"items"`
+}
+
+// completionSource fetches the candidates of one kind.
+type completionSource struct {
+	kind  string
+	fetch func(ctx context.Context) ([]completionItem, error)
+}
+
+var (
+	certCompletion = completionSource{"certs", func(ctx context.Context) ([]completionItem, error) {
+		certs, err := listAll(ctx, client.ListUserSSLCerts)
+		items := make([]completionItem, len(certs))
+		for i, c := range certs {
+			items[i] = completionItem{c.UUID, describe(c.Comment, c.NotAfter)}
+		}
+		return items, err
+	}}
+	caCompletion      = caCompletionSource("cas", false)
+	adminCACompletion = caCompletionSource("admin-cas", true)
+	userCompletion    = completionSource{"users", func(ctx context.Context) ([]completionItem, error) {
+		users, err := listAll(ctx, client.ListAdminUsers)
+		items := make([]completionItem, len(users))
+		for i, u := range users {
+			items[i] = completionItem{u.Username, describe(u.DisplayName, api.RoleName(u.Role))}
+		}
+		return items, err
+	}}
+)
+
+// caCompletionSource lists the CAs of the current user, or every CA when admin is set.
+func caCompletionSource(kind string, admin bool) completionSource {
+	return completionSource{kind, func(ctx context.Context) ([]completionItem, error) {
+		cas, err := listCAs(ctx, admin)
+		items := make([]completionItem, len(cas))
+		for i, ca := range cas {
+			items[i] = completionItem{ca.UUID, describe(ca.Comment, ca.CAType())}
+		}
+		return items, err
+	}}
+}
+
+// describe joins the non-empty parts of a completion description.
+func describe(parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, " · ")
+}
+
+// candidates returns the cached candidates of src, refreshing them when the
+// cache is older than completionTTL. Errors yield no candidates: completion
+// must never print to the terminal.
+func (src completionSource) candidates() []completionItem {
+	if initCompletion() != nil {
+		return nil
+	}
+	path := completionCachePath(src.kind)
+	if data, err := os.ReadFile(path); err == nil {
+		var cache completionCache
+		if json.Unmarshal(data, &cache) == nil && time.Since(cache.Fetched) < completionTTL {
+			return cache.Items
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+	items, err := src.fetch(ctx)
+	if err != nil {
+		return nil
+	}
+	if data, err := json.Marshal(completionCache{Fetched: time.Now(), Items: items}); err == nil {
+		if os.MkdirAll(filepath.Dir(path), 0700) == nil {
+			_ = os.WriteFile(path, data, 0600)
+		}
+	}
+	return items
+}
+
+// completionOnce runs initCompletion's setup once; completionErr is its result.
+var (
+	completionOnce sync.Once
+	completionErr  error
+)
+
+// initCompletion loads the config and builds client for a completion
+// request, once its flags, --server among them, are parsed. Unlike
+// initConfig it prints no warnings and leaves out the debug log, the
+// session hook and the response cache.
+func initCompletion() error {
+	completionOnce.Do(func() {
+		var err error
+		if cfg, err = config.Load(); err != nil {
+			cfg = &config.Config{ServerURL: config.DefaultServerURL}
+		}
+		if serverURL != "" {
+			cfg.UseServer(serverURL)
+		}
+		opts, err := clientOptions()
+		if err != nil {
+			completionErr = err
+			return
+		}
+		c := api.NewClient(cfg.ServerURL, opts...)
+		if cfg.Session != "" {
+			c.SetSession(cfg.Session)
+		}
+		client = c
+	})
+	return completionErr
+}
+
+// clearCompletionCache removes the cached candidates of the current server
+// and session, after a command changed what they list.
+func clearCompletionCache() {
+	matches, _ := filepath.Glob(completionCachePath("*"))
+	for _, path := range matches {
+		_ = os.Remove(path)
+	}
+}
+
+// completionCachePath keys the cache by server and session so different
+// accounts never see each other's candidates.
+func completionCachePath(kind string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	sum := sha256.Sum256([]byte(cfg.ServerURL + "\x00" + cfg.Session))
+	return filepath.Join(dir, config.ConfigDirName, "completion", hex.EncodeToString(sum[:8])+"-"+kind+".json")
+}
+
+// complete returns a ValidArgsFunction offering candidates from src for the
+// positional arguments at the given indexes (or every position when none
+// are given). Values already on the command line are not offered again.
+func complete(src completionSource, positions ...int) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(positions) > 0 && !slices.Contains(positions, len(args)) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return src.filter(args, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeFlag is the flag-value counterpart of complete.
+func completeFlag(src completionSource) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		return src.filter(nil, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func (src completionSource) filter(used []string, prefix string) []cobra.Completion {
+	var out []cobra.Completion
+	for _, item := range src.candidates() {
+		if !strings.HasPrefix(item.Value, prefix) || slices.Contains(used, item.Value) {
+			continue
+		}
+		out = append(out, cobra.CompletionWithDesc(item.Value, item.Desc))
+	}
+	return out
+}
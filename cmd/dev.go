@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api/fake"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mockListen  string
+	mockEmpty   bool
+	mockQuiet   bool
+	mockWithout []string
+)
+
+// devCmd groups tools for developing against CertVault.
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Tools for developing and testing against CertVault",
+	// The mock server is a server; it must not fail over the client config.
+	Annotations: map[string]string{noServerAnnotation: ""},
+}
+
+// devMockServerCmd serves an in-memory CertVault until interrupted.
+var devMockServerCmd = &cobra.Command{
+	Use:   "mock-server",
+	Short: "Run an in-memory CertVault server",
+	Long: `Run an in-memory CertVault server implementing the whole API used by cvx,
+with real X.509 certificates. All state is lost when it stops.
+
+Unless --empty is given it starts with demo data: users superadmin, admin,
+alice and bob (each with their username as password), a root and an issuing
+CA, and a few certificates.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := fake.NewStore()
+		if !mockEmpty {
+			if err := store.Seed(); err != nil {
+				return fmt.Errorf("seed mock server: %w", err)
+			}
+		}
+		var without []api.Capability
+		for _, name := range mockWithout {
+			c := api.Capability(name)
+			if !slices.Contains(api.AllCapabilities(), c) {
+				return fmt.Errorf("unknown capability %q (want one of %v)", name, api.AllCapabilities())
+			}
+			without = append(without, c)
+		}
+		store.Without(without...)
+		ln, err := net.Listen("tcp", mockListen)
+		if err != nil {
+			return err
+		}
+		handler := store.Handler()
+		if !mockQuiet {
+			handler = logRequests(handler)
+		}
+		srv := httptest.NewUnstartedServer(handler)
+		srv.Listener.Close()
+		srv.Listener = ln
+		srv.Start()
+		defer srv.Close()
+
+		fmt.Printf("✓ Mock CertVault server listening on %s\n", srv.URL)
+		if mockEmpty {
+			fmt.Printf("  Log in as %s / %s\n", fake.DefaultUsername, fake.DefaultPassword)
+		} else {
+			fmt.Println("  Log in as superadmin, admin, alice or bob; passwords equal usernames")
+		}
+		fmt.Printf("  cvx --server %s login -u %s\n", srv.URL, fake.DefaultUsername)
+		fmt.Printf("  cvx --server %s cert list\n", srv.URL)
+		fmt.Println("Press Ctrl+C to stop.")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		<-ctx.Done()
+		return nil
+	},
+}
+
+// logRequests prints one line per request to stderr.
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		fmt.Fprintf(os.Stderr, "%s %-6s %s (%s)\n", start.Format("15:04:05"), r.Method, r.URL.RequestURI(), time.Since(start).Round(time.Microsecond))
+	})
+}
+
+func init() {
+	devMockServerCmd.Flags().StringVar(&mockListen, "listen", "localhost:1888", "Address to listen on")
+	devMockServerCmd.Flags().BoolVar(&mockEmpty, "empty", false, "Start with only the superadmin instead of demo data")
+	devMockServerCmd.Flags().BoolVarP(&mockQuiet, "quiet", "q", false, "Do not log requests")
+	devMockServerCmd.Flags().StringSliceVar(&mockWithout, "without", nil, "Act like an older server lacking these capabilities: stats, sessions, ca-bindings, tools")
+
+	devCmd.AddCommand(devMockServerCmd)
+	rootCmd.AddCommand(devCmd)
+}
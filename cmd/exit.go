@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+// Exit codes of cvx. They are part of the CLI contract for scripts, so
+// existing values must never change.
+const (
+	exitOK           = 0
+	exitError        = 1 // any other failure, including usage errors
+	exitUnauthorized = 3 // not logged in or session expired
+	exitForbidden    = 4 // the user's role does not allow the action
+	exitNotFound     = 5
+	exitConflict     = 6
+	exitValidation   = 7 // the server rejected the request's input
+	exitServer       = 8 // the server failed (HTTP 5xx)
+	exitUnsupported  = 9 // the server is too old for the action
+)
+
+// exitCode maps err to the process exit code.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, api.ErrUnauthorized):
+		return exitUnauthorized
+	case errors.Is(err, api.ErrForbidden):
+		return exitForbidden
+	case errors.Is(err, api.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, api.ErrConflict):
+		return exitConflict
+	case errors.Is(err, api.ErrValidation):
+		return exitValidation
+	case errors.Is(err, api.ErrServer):
+		return exitServer
+	case errors.Is(err, api.ErrUnsupported):
+		return exitUnsupported
+	}
+	return exitError
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{err: nil, want: exitOK},
+		{err: fmt.Errorf("login: %w", api.ErrUnauthorized), want: exitUnauthorized},
+		{err: fmt.Errorf("delete: %w", api.ErrForbidden), want: exitForbidden},
+		{err: fmt.Errorf("get: %w", api.ErrNotFound), want: exitNotFound},
+		{err: fmt.Errorf("create: %w", api.ErrConflict), want: exitConflict},
+		{err: fmt.Errorf("create: %w", api.ErrValidation), want: exitValidation},
+		{err: fmt.Errorf("list: %w", api.ErrServer), want: exitServer},
+		{err: &api.UnsupportedError{Capability: api.CapSessions}, want: exitUnsupported},
+		{err: fmt.Errorf("aborted"), want: exitError},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCommandExitCode(t *testing.T) {
+	isolate(t)
+	store, url := mockServer(t)
+	store.Without(api.CapSessions)
+	alice := store.Client()
+	if err := alice.Login(context.Background(), "alice", "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		session string
+		args    []string
+		want    int
+	}{
+		{name: "logged in", session: alice.GetSession(), args: []string{"whoami"}, want: exitOK},
+		{name: "not logged in", args: []string{"whoami"}, want: exitUnauthorized},
+		{name: "missing CA", session: alice.GetSession(), args: []string{"ca", "get", "no-such-ca"}, want: exitNotFound},
+		{name: "admin CAs as a user", session: alice.GetSession(), args: []string{"ca", "get", "--admin", "no-such-ca"}, want: exitForbidden},
+		{name: "unsupported", session: alice.GetSession(), args: []string{"session", "list"}, want: exitUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CERTVAULT_SESSION", tt.session)
+			_, err := run(t, append([]string{"--server", url}, tt.args...)...)
+			if got := exitCode(err); got != tt.want {
+				t.Errorf("cvx %v: %v, exit code %d, want %d", tt.args, err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/spf13/pflag"
+)
+
+// passwordFlags collects the non-interactive ways a command can receive a password.
+// Sources are consulted in order: stdin, file descriptor, file, environment
+// variable, and finally an interactive prompt when stdin is a terminal.
+type passwordFlags struct {
+	stdin bool
+	fd    int
+	file  string
+	env   string // name of the environment variable to read
+}
+
+// register adds the password flags to fs. prefix is prepended to each flag name
+// (e.g. "key-" yields --key-password-stdin).
+func (p *passwordFlags) register(fs *pflag.FlagSet, prefix, what string) {
+	fs.BoolVar(&p.stdin, prefix+"password-stdin", false, "Read the "+what+" from stdin")
+	fs.IntVar(&p.fd, prefix+"password-fd", -1, "Read the "+what+" from an open file descriptor")
+	fs.StringVar(&p.file, prefix+"password-file", "", "Read the "+what+" from a file")
+}
+
+// given reports whether a non-interactive password source was configured.
+// Commands where the password is optional use it to avoid prompting.
+func (p *passwordFlags) given() bool {
+	return p.stdin || p.fd >= 0 || p.file != "" || (p.env != "" && os.Getenv(p.env) != "")
+}
+
+// read returns the password from the first configured source.
+func (p *passwordFlags) read(prompt string) (string, error) {
+	switch {
+	case p.stdin:
+		return readSecretLine(os.Stdin)
+	case p.fd >= 0:
+		f := os.NewFile(uintptr(p.fd), "password-fd")
+		if f == nil {
+			return "", fmt.Errorf("invalid password file descriptor %d", p.fd)
+		}
+		defer f.Close()
+		return readSecretLine(f)
+	case p.file != "":
+		f, err := os.Open(p.file)
+		if err != nil {
+			return "", fmt.Errorf("read password file: %w", err)
+		}
+		defer f.Close()
+		return readSecretLine(f)
+	case p.env != "" && os.Getenv(p.env) != "":
+		return os.Getenv(p.env), nil
+	}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return "", fmt.Errorf("no password given: use --password-stdin, --password-fd, --password-file or $%s", p.env)
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	pw, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("read password: %w", err)
+	}
+	return string(pw), nil
+}
+
+// readSecretLine reads the first line from r, without the trailing newline.
+func readSecretLine(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", errors.New("empty password")
+	}
+	return line, nil
+}
+
+// writeOutput writes data to path, or to stdout when path is empty or "-".
+// Like the TUI export, parent directories are created and files are written
+// with mode 0600 since they may contain private keys.
+func writeOutput(path string, data []byte) error {
+	if path == "" || path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Encodings accepted by the --format flag of export commands.
+const (
+	formatPEM = "pem"
+	formatDER = "der"
+)
+
+// decodeMaterial base64-decodes a PEM string returned by the cert and privkey
+// endpoints and converts it to the requested encoding.
+func decodeMaterial(encoded, format string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	switch format {
+	case formatPEM, "":
+		return data, nil
+	case formatDER:
+		return pemToDER(data)
+	}
+	return nil, fmt.Errorf("unknown format %q (want %s or %s)", format, formatPEM, formatDER)
+}
+
+// checkChainFormat rejects --format der for a chain: a DER file holds a
+// single certificate, and concatenated ones are read as the first only.
+func checkChainFormat(format string, chain bool) error {
+	if format == formatDER && chain {
+		return errors.New("--format der holds a single certificate; use --format pem with --chain or --root")
+	}
+	return nil
+}
+
+// pemToDER returns the DER bytes of the single PEM block in data.
+func pemToDER(data []byte) ([]byte, error) {
+	block, rest := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	if next, _ := pem.Decode(rest); next != nil {
+		return nil, errors.New("DER holds a single certificate, but the response has several; use --format pem")
+	}
+	return block.Bytes, nil
+}
+
+// readNames collects names (e.g. usernames) from args and, if file is set,
+// from a file with one name per line ("-" reads stdin). Blank lines and
+// lines starting with '#' are ignored; duplicates are dropped.
+func readNames(args []string, file string) ([]string, error) {
+	names := append([]string(nil), args...)
+	if file != "" {
+		r := io.Reader(os.Stdin)
+		if file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			r = f
+		}
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			names = append(names, line)
+		}
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+	}
+	seen := make(map[string]bool, len(names))
+	out := names[:0]
+	for _, n := range names {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no names given: pass them as arguments or with --file")
+	}
+	return out, nil
+}
+
+// checkAuth adds a hint to run `cvx login` when err indicates a missing or expired session.
+func checkAuth(err error) error {
+	if errors.Is(err, api.ErrUnauthorized) {
+		return fmt.Errorf("%w (run `cvx login` first)", err)
+	}
+	return err
+}
+
+// listAll walks every page of a paginated list endpoint and returns all items.
+// The next page is fetched while the current one is collected.
+func listAll[T any](ctx context.Context, fetch api.PageFunc[T]) ([]T, error) {
+	return api.CollectPages(ctx, fetch, api.PageOptions{Prefetch: true})
+}
+
+// parseWithin parses a look-ahead window such as "30d", "2w" or "12h".
+// A bare number is interpreted as days.
+func parseWithin(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil {
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	if unit != 0 {
+		n, err := strconv.Atoi(strings.TrimSpace(s[:len(s)-1]))
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * unit, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 30d, 2w or 12h)", s)
+	}
+	return d, nil
+}
+
+// expiresWithin reports whether notAfter falls before now+window.
+// Dates that cannot be parsed never match.
+func expiresWithin(notAfter string, window time.Duration) bool {
+	t, ok := api.ParseTime(notAfter)
+	return ok && time.Until(t) <= window
+}
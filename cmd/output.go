@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/output"
+)
+
+var (
+	// outputFlag is the raw value of the global --output flag.
+	outputFlag string
+	// outputFormat is outputFlag parsed by rootCmd's PersistentPreRunE.
+	outputFormat output.Format
+)
+
+// printOutput writes data to stdout in the format selected by --output.
+func printOutput(data any) error {
+	return output.Print(os.Stdout, outputFormat, data)
+}
+
+// Statuses of a result.
+const (
+	statusOK     = "ok"
+	statusFailed = "failed"
+	statusDryRun = "dry-run"
+)
+
+// result is the outcome of a change made by a command, such as one item of
+// a bulk delete or a role update.
+type result struct {
+	Action string `json:"action"`
+	Item   string `json:"item,omitempty"`
+	Status string `json:"status"`
+	// Detail is e.g. the new role or the label of a bulk item.
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// textOutput reports whether --output selects the default table format, in
+// which changes are reported as decorated text lines instead of results.
+func textOutput() bool {
+	return outputFormat.Name == output.Table
+}
+
+// printResult prints text, a line such as "✓ Updated alice", in the table
+// format and r in every other format.
+func printResult(text string, r result) error {
+	if textOutput() {
+		fmt.Println(text)
+		return nil
+	}
+	return printOutput(r)
+}
+
+func init() {
+	output.Register(
+		output.Column[result]{Header: "ACTION", Value: func(r result) string { return r.Action }},
+		output.Column[result]{Header: "ITEM", Value: func(r result) string { return r.Item }},
+		output.Column[result]{Header: "STATUS", Value: func(r result) string { return r.Status }},
+		output.Column[result]{Header: "DETAIL", Value: func(r result) string { return r.Detail }},
+		output.Column[result]{Header: "ERROR", Value: func(r result) string { return r.Error }},
+	)
+}
@@ -6,17 +6,21 @@ import (
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/spf13/cobra"
 	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
 	"github.com/gregPerlinLi/CertVaultCLIX/internal/config"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/output"
 	"github.com/gregPerlinLi/CertVaultCLIX/internal/tui"
 	"github.com/gregPerlinLi/CertVaultCLIX/internal/version"
+	"github.com/spf13/cobra"
 )
 
 var (
 	serverURL string
 	cfg       *config.Config
-	client    *api.Client
+	client    api.Service
+	// clientErr is a configuration error found while building client; it is
+	// reported by the command about to use the client.
+	clientErr error
 )
 
 // rootCmd is the root cobra command.
@@ -29,6 +33,31 @@ for the CertVault self-signed SSL certificate management platform.
 Run without arguments to launch the interactive TUI, or use
 subcommands for scripting and automation.`,
 	Version: version.String(),
+	// Execute prints errors itself; API failures should not dump usage text.
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if !usesServer(cmd) {
+			return nil
+		}
+		initConfig()
+		if clientErr != nil {
+			return clientErr
+		}
+		if insecureTLS {
+			fmt.Fprintln(os.Stderr, insecureWarning)
+		}
+		// The TUI labels offline data itself; printing would garble it.
+		if responseCache != nil && cmd.HasParent() {
+			responseCache.OnOffline(warnOffline)
+		}
+		// Parse --output up front so a typo fails before any request is sent.
+		var err error
+		if outputFormat, err = output.Parse(outputFlag); err != nil {
+			return err
+		}
+		return checkCapability(cmd)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runTUI()
 	},
@@ -36,17 +65,43 @@ subcommands for scripting and automation.`,
 
 // Execute runs the root command.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	if debugLog != nil {
+		if cerr := debugLog.Close(); cerr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not close debug log: %v\n", cerr)
+		}
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
+	}
+}
+
+// noServerAnnotation marks a command, and its subcommands, that does not
+// talk to a CertVault server and so needs no config or client.
+const noServerAnnotation = "no-server"
+
+// usesServer reports whether cmd needs the config and client. Completion
+// requests build their own client once the completed command's flags are
+// parsed, see initCompletion.
+func usesServer(cmd *cobra.Command) bool {
+	if cmd.Name() == cobra.ShellCompRequestCmd {
+		return false
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		if _, ok := c.Annotations[noServerAnnotation]; ok {
+			return false
+		}
 	}
+	return true
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
 	rootCmd.PersistentFlags().StringVar(&serverURL, "server", "", "CertVault server URL (overrides config)")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", output.Table, output.Usage)
 }
 
+// initConfig loads the config and builds client from it and the flags.
 func initConfig() {
 	var err error
 	cfg, err = config.Load()
@@ -56,31 +111,44 @@ func initConfig() {
 	}
 
 	if serverURL != "" {
-		cfg.ServerURL = serverURL
+		cfg.UseServer(serverURL)
 	}
 
-	client = api.NewClient(cfg.ServerURL)
+	opts, err := clientOptions()
+	if err == nil {
+		var trace api.Option
+		if trace, err = debugOption(); trace != nil {
+			opts = append(opts, trace)
+		}
+	}
+	clientErr = err
+	c := api.NewClient(cfg.ServerURL, opts...)
 	if cfg.Session != "" {
-		client.SetSession(cfg.Session)
+		c.SetSession(cfg.Session)
+	}
+	c.OnSessionChange(persistSession)
+	client = c
+	if !cfg.Cache.Disabled {
+		responseCache = newResponseCache(c)
+		client = responseCache
 	}
 }
 
+// persistSession saves the session as soon as the client sees it change, so
+// a crashed or killed TUI does not lose it. Sessions of a --server or
+// CERTVAULT_URL server are saved apart from the configured one. Errors are
+// ignored here; the commands that must report them call cfg.SetSession
+// themselves.
+func persistSession(session string) {
+	_ = cfg.SetSession(session)
+}
+
 func runTUI() error {
 	app := tui.NewApp(client, cfg)
 	p := tea.NewProgram(app, tea.WithAltScreen(), tea.WithMouseCellMotion())
-	finalModel, err := p.Run()
-	if err != nil {
+	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("TUI error: %w", err)
 	}
-
-	// Save session on exit
-	if finalApp, ok := finalModel.(*tui.App); ok {
-		session := finalApp.Client().GetSession()
-		if session != "" && cfg != nil {
-			cfg.Session = session
-			_ = config.Save(cfg)
-		}
-	}
 	return nil
 }
 
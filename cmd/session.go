@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sessionRevokeBulk   bulkFlags
+	sessionRevokeAllYes bool
+
+	saSessionUser     string
+	saForceLogoutBulk bulkFlags
+	saForceLogoutFile string
+)
+
+// sessionCmd groups the commands for the current user's login sessions.
+var sessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "List and revoke your login sessions",
+}
+
+// sessionListCmd prints every session of the current user.
+var sessionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your login sessions",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return printSessions(context.Background(), client.ListUserSessions)
+	},
+}
+
+// sessionRevokeCmd logs out individual sessions.
+var sessionRevokeCmd = &cobra.Command{
+	Use:   "revoke <uuid>...",
+	Short: "Log out one or more of your sessions",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return sessionRevokeBulk.run(context.Background(), "revoke", nameItems(args), client.LogoutSession)
+	},
+}
+
+// sessionRevokeAllCmd logs out every session of the current user, including
+// this one, and clears the saved session.
+var sessionRevokeAllCmd = &cobra.Command{
+	Use:   "revoke-all",
+	Short: "Log out all of your sessions, including this one",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !sessionRevokeAllYes {
+			ok, err := confirm("Log out all of your sessions?")
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errors.New("aborted")
+			}
+		}
+		if err := client.LogoutAllSessions(context.Background()); err != nil {
+			return checkAuth(err)
+		}
+		if err := cfg.SetSession(""); err != nil {
+			return fmt.Errorf("clear session: %w", err)
+		}
+		return printResult("✓ Logged out of all sessions", result{Action: "revoke-all", Item: cfg.ServerURL, Status: statusOK})
+	},
+}
+
+// superadminSessionCmd groups the session commands across all users.
+var superadminSessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "List sessions of all users and force logouts",
+}
+
+// saSessionListCmd prints the sessions of every user, or of one user with --user.
+var saSessionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the sessions of all users",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list := client.ListAllSessions
+		if saSessionUser != "" {
+			list = func(ctx context.Context, page, size int) (*api.PageDTO[api.LoginRecord], error) {
+				return client.ListUserSessionsBySuperadmin(ctx, saSessionUser, page, size)
+			}
+		}
+		return printSessions(context.Background(), list)
+	},
+}
+
+// saForceLogoutCmd ends every session of the given users.
+var saForceLogoutCmd = &cobra.Command{
+	Use:   "force-logout [username...]",
+	Short: "End every session of one or more users",
+	Long: `End every session of one or more users.
+
+Usernames are taken from the arguments and from --file (one per line,
+"-" reads stdin; blank lines and # comments are ignored).`,
+	Example: `  cvx superadmin session force-logout mallory --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, err := readNames(args, saForceLogoutFile)
+		if err != nil {
+			return err
+		}
+		return saForceLogoutBulk.run(context.Background(), "force-logout", nameItems(names), client.ForceLogoutUser)
+	},
+}
+
+// printSessions walks every page of a session listing and prints it.
+func printSessions(ctx context.Context, list func(ctx context.Context, page, size int) (*api.PageDTO[api.LoginRecord], error)) error {
+	sessions, err := listAll(ctx, list)
+	if err != nil {
+		return checkAuth(err)
+	}
+	if sessions == nil {
+		sessions = []api.LoginRecord{}
+	}
+	return printOutput(sessions)
+}
+
+func init() {
+	sessionRevokeBulk.register(sessionRevokeCmd.Flags())
+	sessionRevokeAllCmd.Flags().BoolVarP(&sessionRevokeAllYes, "yes", "y", false, "Do not ask for confirmation")
+	saSessionListCmd.Flags().StringVar(&saSessionUser, "user", "", "Only list the sessions of this user")
+	saForceLogoutCmd.Flags().StringVar(&saForceLogoutFile, "file", "", `File with one username per line ("-" for stdin)`)
+	saForceLogoutBulk.register(saForceLogoutCmd.Flags())
+
+	_ = saSessionListCmd.RegisterFlagCompletionFunc("user", completeFlag(userCompletion))
+	saForceLogoutCmd.ValidArgsFunction = complete(userCompletion)
+
+	sessionCmd.AddCommand(sessionListCmd)
+	sessionCmd.AddCommand(sessionRevokeCmd)
+	sessionCmd.AddCommand(sessionRevokeAllCmd)
+	rootCmd.AddCommand(sessionCmd)
+
+	superadminSessionCmd.AddCommand(saSessionListCmd)
+	superadminSessionCmd.AddCommand(saForceLogoutCmd)
+	superadminCmd.AddCommand(superadminSessionCmd)
+}
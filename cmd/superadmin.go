@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var (
+	saUserDisplayName string
+	saUserEmail       string
+	saUserRole        string
+	saUserPassword    = passwordFlags{env: "CERTVAULT_NEW_PASSWORD"}
+	saUserDeleteBulk  bulkFlags
+
+	saImportDryRun bool
+
+	saBatchDeleteFile string
+	saBatchDeleteBulk bulkFlags
+)
+
+// superadminCmd groups the commands that need the Superadmin role.
+var superadminCmd = &cobra.Command{
+	Use:   "superadmin",
+	Short: "Superadministration commands (Superadmin role)",
+}
+
+// superadminUserCmd groups the user management commands.
+var superadminUserCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Create, update and delete users",
+}
+
+// saUserCreateCmd creates a single user.
+var saUserCreateCmd = &cobra.Command{
+	Use:   "create <username>",
+	Short: "Create a user",
+	Long: `Create a user. The new user's password is read from --password-stdin,
+--password-fd, --password-file, $CERTVAULT_NEW_PASSWORD or a prompt.`,
+	Example: `  cvx superadmin user create alice --display-name "Alice Liddell" --email alice@example.com --role admin`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		role, err := parseRole(saUserRole)
+		if err != nil {
+			return err
+		}
+		password, err := saUserPassword.read("Password for " + args[0] + ": ")
+		if err != nil {
+			return err
+		}
+		user, err := client.CreateUser(context.Background(), api.CreateUserRequest{
+			Username:    args[0],
+			DisplayName: saUserDisplayName,
+			Email:       saUserEmail,
+			Password:    password,
+			Role:        role,
+		})
+		if err != nil {
+			return checkAuth(err)
+		}
+		clearCompletionCache()
+		return printOutput(user)
+	},
+}
+
+// saUserUpdateCmd updates a user's display name and email.
+var saUserUpdateCmd = &cobra.Command{
+	Use:   "update <username>",
+	Short: "Update a user's display name or email",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cmd.Flags().Changed("display-name") && !cmd.Flags().Changed("email") {
+			return errors.New("nothing to update: pass --display-name and/or --email")
+		}
+		req := api.UpdateSuperadminUserRequest{DisplayName: saUserDisplayName, Email: saUserEmail}
+		if err := client.UpdateSuperadminUser(context.Background(), args[0], req); err != nil {
+			return checkAuth(err)
+		}
+		clearCompletionCache()
+		return printResult("✓ Updated "+args[0], result{Action: "update", Item: args[0], Status: statusOK})
+	},
+}
+
+// saUserRoleCmd changes a user's role.
+var saUserRoleCmd = &cobra.Command{
+	Use:   "role <username> <user|admin|superadmin>",
+	Short: "Change a user's role",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		role, err := parseRole(args[1])
+		if err != nil {
+			return err
+		}
+		req := api.UpdateUserRoleRequest{Username: args[0], Role: role}
+		if err := client.UpdateUserRole(context.Background(), req); err != nil {
+			return checkAuth(err)
+		}
+		clearCompletionCache()
+		return printResult(fmt.Sprintf("✓ %s is now %s", args[0], api.RoleName(role)),
+			result{Action: "role", Item: args[0], Status: statusOK, Detail: api.RoleName(role)})
+	},
+}
+
+// saUserPasswdCmd sets a new password for a user.
+var saUserPasswdCmd = &cobra.Command{
+	Use:   "passwd <username>",
+	Short: "Set a user's password",
+	Long: `Set a user's password. The new password is read from --password-stdin,
+--password-fd, --password-file, $CERTVAULT_NEW_PASSWORD or a prompt.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		password, err := saUserPassword.read("New password for " + args[0] + ": ")
+		if err != nil {
+			return err
+		}
+		req := api.UpdateSuperadminUserRequest{Password: password}
+		if err := client.UpdateSuperadminUser(context.Background(), args[0], req); err != nil {
+			return checkAuth(err)
+		}
+		return printResult("✓ Password changed for "+args[0], result{Action: "passwd", Item: args[0], Status: statusOK})
+	},
+}
+
+// saUserDeleteCmd deletes a single user.
+var saUserDeleteCmd = &cobra.Command{
+	Use:   "delete <username>",
+	Short: "Delete a user",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return saUserDeleteBulk.run(context.Background(), "delete", nameItems(args), client.DeleteSuperadminUser)
+	},
+}
+
+// saUserImportCmd creates users in bulk from a CSV file.
+var saUserImportCmd = &cobra.Command{
+	Use:   "import <users.csv>",
+	Short: "Create users from a CSV file",
+	Long: `Create users from a CSV file ("-" reads stdin) in a single batch.
+
+The first row is a header naming the columns: username and password are
+required; displayName, email and role (user, admin, superadmin or 1-3,
+default user) are optional. Every row is validated before anything is
+sent, so a file with errors creates no users.
+
+  username,displayName,email,password,role
+  alice,Alice Liddell,alice@example.com,s3cret!,admin
+  bob,Bob,bob@example.com,hunter22,`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var r io.Reader = os.Stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		users, err := parseUserCSV(r)
+		if err != nil {
+			return err
+		}
+		items := make([]bulkItem, len(users))
+		for i, u := range users {
+			items[i] = bulkItem{id: u.Username, label: api.RoleName(u.Role)}
+		}
+		if saImportDryRun {
+			if !textOutput() {
+				return printBatch("", "create", statusDryRun, items)
+			}
+			for _, item := range items {
+				fmt.Printf("would create %s\n", item)
+			}
+			fmt.Printf("Dry run: %d user(s) are valid.\n", len(users))
+			return nil
+		}
+		if err := client.BatchCreateUsers(context.Background(), users); err != nil {
+			return checkAuth(err)
+		}
+		clearCompletionCache()
+		return printBatch(fmt.Sprintf("✓ Created %d user(s)", len(users)), "create", statusOK, items)
+	},
+}
+
+// saUserBatchDeleteCmd deletes many users with a single request.
+var saUserBatchDeleteCmd = &cobra.Command{
+	Use:   "batch-delete [username...]",
+	Short: "Delete many users in a single batch",
+	Long: `Delete many users in a single batch request.
+
+Usernames are taken from the arguments and from --file (one per line,
+"-" reads stdin; blank lines and # comments are ignored).`,
+	Example: `  cvx superadmin user batch-delete --file leavers.txt --dry-run
+  cvx superadmin user batch-delete carol dave --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, err := readNames(args, saBatchDeleteFile)
+		if err != nil {
+			return err
+		}
+		items := nameItems(names)
+		if ok, err := saBatchDeleteBulk.proceed("delete", items); !ok {
+			return err
+		}
+		if err := client.BatchDeleteUsers(context.Background(), names); err != nil {
+			return checkAuth(err)
+		}
+		clearCompletionCache()
+		return printBatch(fmt.Sprintf("✓ Deleted %d user(s)", len(names)), "delete", statusOK, items)
+	},
+}
+
+// roleValues maps role names accepted on the command line to API role values.
+var roleValues = map[string]int{"user": 1, "admin": 2, "superadmin": 3}
+
+// parseRole accepts a role name (case-insensitive) or its numeric API value.
+func parseRole(s string) (int, error) {
+	if role, ok := roleValues[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return role, nil
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(s)); err == nil && n >= 1 && n <= 3 {
+		return n, nil
+	}
+	return 0, fmt.Errorf("invalid role %q (want user, admin or superadmin)", s)
+}
+
+// parseUserCSV reads and validates a user import file. All row errors are
+// reported together so the file can be fixed in one go.
+func parseUserCSV(r io.Reader) ([]api.CreateUserRequest, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("CSV file is empty")
+	}
+
+	// Header names are matched loosely: "displayName", "display_name" and
+	// "Display Name" all refer to the same column.
+	cols := map[string]int{}
+	for i, h := range records[0] {
+		h = strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(h))
+		switch h {
+		case "username", "displayname", "email", "password", "role":
+			cols[h] = i
+		default:
+			return nil, fmt.Errorf("line 1: unknown column %q", records[0][i])
+		}
+	}
+	for _, required := range []string{"username", "password"} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("line 1: missing required column %q", required)
+		}
+	}
+	field := func(rec []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+
+	var users []api.CreateUserRequest
+	var problems []string
+	seen := map[string]int{}
+	for n, rec := range records[1:] {
+		line := n + 2
+		u := api.CreateUserRequest{
+			Username:    field(rec, "username"),
+			DisplayName: field(rec, "displayname"),
+			Email:       field(rec, "email"),
+			Password:    field(rec, "password"),
+			Role:        roleValues["user"],
+		}
+		switch {
+		case u.Username == "":
+			problems = append(problems, fmt.Sprintf("line %d: username is empty", line))
+			continue
+		case seen[u.Username] != 0:
+			problems = append(problems, fmt.Sprintf("line %d: duplicate username %q (first on line %d)", line, u.Username, seen[u.Username]))
+			continue
+		}
+		seen[u.Username] = line
+		if u.Password == "" {
+			problems = append(problems, fmt.Sprintf("line %d: password for %q is empty", line, u.Username))
+		}
+		if u.Email != "" && !strings.Contains(u.Email, "@") {
+			problems = append(problems, fmt.Sprintf("line %d: invalid email %q", line, u.Email))
+		}
+		if role := field(rec, "role"); role != "" {
+			if u.Role, err = parseRole(role); err != nil {
+				problems = append(problems, fmt.Sprintf("line %d: %v", line, err))
+			}
+		}
+		users = append(users, u)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%d invalid row(s), nothing imported:\n  %s", len(problems), strings.Join(problems, "\n  "))
+	}
+	if len(users) == 0 {
+		return nil, errors.New("CSV file has no users")
+	}
+	return users, nil
+}
+
+func init() {
+	for _, c := range []*cobra.Command{saUserCreateCmd, saUserUpdateCmd} {
+		c.Flags().StringVar(&saUserDisplayName, "display-name", "", "Display name")
+		c.Flags().StringVar(&saUserEmail, "email", "", "Email address")
+	}
+	saUserCreateCmd.Flags().StringVar(&saUserRole, "role", "user", "Role: user, admin or superadmin")
+	for _, c := range []*cobra.Command{saUserCreateCmd, saUserPasswdCmd} {
+		saUserPassword.register(c.Flags(), "", "new password")
+	}
+	saUserDeleteBulk.register(saUserDeleteCmd.Flags())
+	saUserImportCmd.Flags().BoolVar(&saImportDryRun, "dry-run", false, "Validate the file without creating any users")
+	saUserBatchDeleteCmd.Flags().StringVar(&saBatchDeleteFile, "file", "", `File with one username per line ("-" for stdin)`)
+	saBatchDeleteBulk.register(saUserBatchDeleteCmd.Flags())
+
+	for _, c := range []*cobra.Command{saUserUpdateCmd, saUserPasswdCmd, saUserDeleteCmd} {
+		c.ValidArgsFunction = complete(userCompletion, 0)
+	}
+	saUserRoleCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) == 1 {
+			return []cobra.Completion{"user", "admin", "superadmin"}, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(userCompletion, 0)(cmd, args, toComplete)
+	}
+	saUserBatchDeleteCmd.ValidArgsFunction = complete(userCompletion)
+	_ = saUserCreateCmd.RegisterFlagCompletionFunc("role", cobra.FixedCompletions([]cobra.Completion{"user", "admin", "superadmin"}, cobra.ShellCompDirectiveNoFileComp))
+
+	superadminUserCmd.AddCommand(saUserCreateCmd)
+	superadminUserCmd.AddCommand(saUserUpdateCmd)
+	superadminUserCmd.AddCommand(saUserRoleCmd)
+	superadminUserCmd.AddCommand(saUserPasswdCmd)
+	superadminUserCmd.AddCommand(saUserDeleteCmd)
+	superadminUserCmd.AddCommand(saUserImportCmd)
+	superadminUserCmd.AddCommand(saUserBatchDeleteCmd)
+	superadminCmd.AddCommand(superadminUserCmd)
+	rootCmd.AddCommand(superadminCmd)
+}
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/certfmt"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	toolsAnalyzeKey  bool
+	toolsKeyPassword = passwordFlags{env: "CERTVAULT_KEY_PASSWORD"}
+
+	toolsConvertTo      string
+	toolsConvertKey     string
+	toolsConvertFile    string
+	toolsConvertPFXPass = passwordFlags{env: "CERTVAULT_PFX_PASSWORD"}
+)
+
+// toolsCmd groups the certificate utilities also offered by the Tools view.
+var toolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "Analyze and convert certificates and keys",
+}
+
+// toolsAnalyzeCmd analyzes certificate or private key files.
+var toolsAnalyzeCmd = &cobra.Command{
+	Use:   "analyze [file...]",
+	Short: "Analyze certificates or private keys",
+	Long: `Analyze certificates (or private keys with --key) on the server.
+
+Files may be PEM or DER; "-" or no file reads stdin. Every file is
+analyzed even if an earlier one fails.
+
+The default output is the same report as the TUI Tools view. With any
+other --output format a single file prints the analysis itself and
+several files print a list of {"file", "analysis", "error"} objects.`,
+	Example: `  cvx tools analyze server.pem
+  cvx tools analyze certs/*.pem -o json | jq '.[] | {file, notAfter: .analysis.notAfter}'
+  cvx tools analyze certs/*.pem -o csv
+  cvx tools analyze --key key.pem --key-password-file pass.txt`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			args = []string{"-"}
+		}
+		var password string
+		if toolsAnalyzeKey && toolsKeyPassword.given() {
+			var err error
+			if password, err = toolsKeyPassword.read("Key password: "); err != nil {
+				return err
+			}
+		}
+
+		ctx := context.Background()
+		text := outputFormat.Name == output.Table
+		width := terminalWidth()
+		results := make([]analysisResult, 0, len(args))
+		failed := 0
+		for i, path := range args {
+			r := analysisResult{File: path}
+			out, err := analyzeFile(ctx, path, password, width)
+			if err != nil {
+				failed++
+				r.Error = checkAuth(err).Error()
+				fmt.Fprintf(os.Stderr, "✗ %s: %s\n", path, r.Error)
+			} else {
+				r.Analysis = out.analysis
+			}
+			results = append(results, r)
+			if err != nil || !text {
+				continue
+			}
+			if len(args) > 1 {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("==> %s <==\n", path)
+			}
+			fmt.Println(strings.TrimRight(out.text, "\n"))
+		}
+		if !text {
+			var err error
+			switch {
+			case len(results) > 1:
+				err = printOutput(results)
+			case failed == 0:
+				err = printOutput(results[0].Analysis)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if failed > 0 {
+			return errBulkFailed
+		}
+		return nil
+	},
+}
+
+// analysisResult is the structured output of one analyzed file.
+type analysisResult struct {
+	File     string `json:"file"`
+	Analysis any    `json:"analysis,omitempty"` // *api.CertAnalysis or *api.PrivKeyAnalysis
+	Error    string `json:"error,omitempty"`
+}
+
+// analyzed is the rendered and raw form of one analysis.
+type analyzed struct {
+	analysis any
+	text     string
+}
+
+// analyzeFile sends one certificate or key file to the analyze endpoint.
+func analyzeFile(ctx context.Context, path, password string, width int) (analyzed, error) {
+	data, err := readInput(path)
+	if err != nil {
+		return analyzed{}, err
+	}
+	if toolsAnalyzeKey {
+		encoded := base64.StdEncoding.EncodeToString(ensurePEM(data, "PRIVATE KEY"))
+		a, err := client.AnalyzePrivKey(ctx, encoded, password)
+		if err != nil {
+			return analyzed{}, err
+		}
+		return analyzed{a, certfmt.PrivKeyAnalysis(a)}, nil
+	}
+	encoded := base64.StdEncoding.EncodeToString(ensurePEM(data, "CERTIFICATE"))
+	a, err := client.AnalyzeCert(ctx, encoded)
+	if err != nil {
+		return analyzed{}, err
+	}
+	return analyzed{a, certfmt.CertAnalysis(a, width)}, nil
+}
+
+// toolsConvertCmd converts between PEM, DER and PFX.
+var toolsConvertCmd = &cobra.Command{
+	Use:   "convert [file]",
+	Short: "Convert certificates between PEM, DER and PFX",
+	Long: `Convert a certificate between encodings on the server.
+
+  --to der   PEM certificate to DER
+  --to pem   DER certificate to PEM
+  --to pfx   PEM certificate plus --key to a password-protected PFX
+
+The input file may be "-" or omitted to read stdin. The PFX password is
+read from --password-stdin, --password-fd, --password-file,
+$CERTVAULT_PFX_PASSWORD or a prompt.`,
+	Example: `  cvx tools convert server.pem --to der -f server.der
+  cvx tools convert server.der --to pem
+  cvx tools convert server.pem --to pfx --key server.key --password-file pass.txt -f server.pfx`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := "-"
+		if len(args) == 1 {
+			path = args[0]
+		}
+		if toolsConvertKey != "" && toolsConvertTo != "pfx" {
+			return fmt.Errorf("--key is only used with --to pfx")
+		}
+		input, err := readInput(path)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		var result *api.ConvertResult
+		switch toolsConvertTo {
+		case formatDER:
+			result, err = client.ConvertPEMtoDER(ctx, base64.StdEncoding.EncodeToString(input))
+		case formatPEM:
+			if block, _ := pem.Decode(input); block != nil {
+				return fmt.Errorf("%s is already PEM", path)
+			}
+			result, err = client.ConvertDERtoPEM(ctx, base64.StdEncoding.EncodeToString(input))
+		case "pfx":
+			result, err = convertToPFX(ctx, input)
+		default:
+			return fmt.Errorf("unknown target format %q (want der, pem or pfx)", toolsConvertTo)
+		}
+		if err != nil {
+			return checkAuth(err)
+		}
+		return writeOutput(toolsConvertFile, convertedBytes(result.Data))
+	},
+}
+
+// convertToPFX bundles a PEM certificate with the --key file into a PFX.
+func convertToPFX(ctx context.Context, cert []byte) (*api.ConvertResult, error) {
+	if toolsConvertKey == "" {
+		return nil, fmt.Errorf("--to pfx requires --key")
+	}
+	key, err := os.ReadFile(toolsConvertKey)
+	if err != nil {
+		return nil, err
+	}
+	password, err := toolsConvertPFXPass.read("PFX password: ")
+	if err != nil {
+		return nil, err
+	}
+	return client.ConvertPEMtoPFX(ctx, api.ConvertPEMtoPFXRequest{
+		Cert:     base64.StdEncoding.EncodeToString(cert),
+		PrivKey:  base64.StdEncoding.EncodeToString(key),
+		Password: password,
+	})
+}
+
+// readInput reads a whole file, or stdin when path is empty or "-".
+func readInput(path string) ([]byte, error) {
+	if path == "" || path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// ensurePEM returns data unchanged if it is PEM, and otherwise wraps it as a
+// PEM block of the given type so DER files can be analyzed too.
+func ensurePEM(data []byte, blockType string) []byte {
+	if block, _ := pem.Decode(data); block != nil {
+		return data
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data})
+}
+
+// convertedBytes decodes the data of a conversion result. Binary results
+// (DER, PFX) are base64-encoded by the server; PEM may come back as text.
+func convertedBytes(data string) []byte {
+	if strings.Contains(data, "-----BEGIN") {
+		return []byte(data)
+	}
+	if b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data)); err == nil {
+		return b
+	}
+	return []byte(data)
+}
+
+// terminalWidth returns the width of stdout, or 100 columns when it is not a terminal.
+func terminalWidth() int {
+	if w, _, err := term.GetSize(os.Stdout.Fd()); err == nil && w > 0 {
+		return w
+	}
+	return 100
+}
+
+func init() {
+	cert := func(r analysisResult) *api.CertAnalysis {
+		a, _ := r.Analysis.(*api.CertAnalysis)
+		return a
+	}
+	output.Register(
+		output.Column[analysisResult]{Header: "FILE", Value: func(r analysisResult) string { return r.File }},
+		output.Column[analysisResult]{Header: "SUBJECT", Value: func(r analysisResult) string {
+			if a := cert(r); a != nil {
+				return a.Subject
+			}
+			return ""
+		}},
+		output.Column[analysisResult]{Header: "ALGORITHM", Value: func(r analysisResult) string {
+			switch a := r.Analysis.(type) {
+			case *api.CertAnalysis:
+				return a.Algorithm
+			case *api.PrivKeyAnalysis:
+				return fmt.Sprintf("%s %d", a.Algorithm, a.KeySize)
+			}
+			return ""
+		}},
+		output.Column[analysisResult]{Header: "NOT AFTER", Value: func(r analysisResult) string {
+			if a := cert(r); a != nil {
+				return a.NotAfter
+			}
+			return ""
+		}},
+		output.Column[analysisResult]{Header: "DAYS LEFT", Value: func(r analysisResult) string {
+			if a := cert(r); a != nil {
+				return fmt.Sprint(api.DaysLeft(a.NotAfter))
+			}
+			return ""
+		}},
+		output.Column[analysisResult]{Header: "ERROR", Value: func(r analysisResult) string { return r.Error }},
+	)
+	output.RegisterView(func(r analysisResult) analysisResult {
+		r.Analysis = output.View(r.Analysis)
+		return r
+	})
+
+	toolsAnalyzeCmd.Flags().BoolVar(&toolsAnalyzeKey, "key", false, "Analyze private keys instead of certificates")
+	toolsKeyPassword.register(toolsAnalyzeCmd.Flags(), "key-", "password of an encrypted private key")
+
+	toolsConvertCmd.Flags().StringVar(&toolsConvertTo, "to", "", "Target format: der, pem or pfx")
+	_ = toolsConvertCmd.MarkFlagRequired("to")
+	toolsConvertCmd.Flags().StringVar(&toolsConvertKey, "key", "", "PEM private key file (for --to pfx)")
+	toolsConvertCmd.Flags().StringVarP(&toolsConvertFile, "file", "f", "-", "Output file (- for stdout)")
+	toolsConvertPFXPass.register(toolsConvertCmd.Flags(), "", "PFX password")
+
+	toolsCmd.AddCommand(toolsAnalyzeCmd)
+	toolsCmd.AddCommand(toolsConvertCmd)
+	rootCmd.AddCommand(toolsCmd)
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+func TestToolsConvert(t *testing.T) {
+	isolate(t)
+	store, url := mockServer(t)
+	ctx := context.Background()
+	alice := store.Client()
+	if err := alice.Login(ctx, "alice", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CERTVAULT_SESSION", alice.GetSession())
+
+	cas, err := alice.ListUserCAs(ctx, 1, 1)
+	if err != nil || len(cas.List) == 0 {
+		t.Fatalf("ListUserCAs() = %v, %v", cas, err)
+	}
+	encoded, err := alice.GetUserCACert(ctx, cas.List[0].UUID, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		t.Fatalf("CA certificate is not PEM: %q", certPEM)
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	pemFile := write("ca.pem", certPEM)
+	keyFile := write("ca.key", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+	derFile := filepath.Join(dir, "ca.der")
+	backFile := filepath.Join(dir, "back.pem")
+
+	convert := func(args ...string) error {
+		_, err := run(t, append([]string{"--server", url, "tools", "convert"}, args...)...)
+		return err
+	}
+	if err := convert(pemFile, "--to", "der", "-f", derFile); err != nil {
+		t.Fatalf("PEM to DER: %v", err)
+	}
+	if der, _ := os.ReadFile(derFile); !bytes.Equal(der, block.Bytes) {
+		t.Errorf("PEM to DER wrote %d bytes, want the %d bytes of the certificate", len(der), len(block.Bytes))
+	}
+	if err := convert(derFile, "--to", "pem", "-f", backFile); err != nil {
+		t.Fatalf("DER to PEM: %v", err)
+	}
+	if back, _ := os.ReadFile(backFile); !bytes.Equal(bytes.TrimSpace(back), bytes.TrimSpace(certPEM)) {
+		t.Errorf("DER to PEM wrote %q, want %q", back, certPEM)
+	}
+
+	// The fake accepts the request but cannot encode PKCS#12; a wrongly
+	// encoded certificate or key would be rejected as invalid instead.
+	t.Setenv("CERTVAULT_PFX_PASSWORD", "secret")
+	if err := convert(pemFile, "--to", "pfx", "--key", keyFile); !errors.Is(err, api.ErrServer) {
+		t.Errorf("PEM to PFX: %v, want the fake's not implemented error", err)
+	}
+
+	// Raw PEM is not what the endpoints take.
+	if _, err := alice.ConvertPEMtoDER(ctx, string(certPEM)); !errors.Is(err, api.ErrValidation) {
+		t.Errorf("ConvertPEMtoDER(raw PEM) = %v, want ErrValidation", err)
+	}
+	if _, err := alice.ConvertPEMtoPFX(ctx, api.ConvertPEMtoPFXRequest{Cert: encoded, PrivKey: string(keyDER)}); !errors.Is(err, api.ErrValidation) {
+		t.Errorf("ConvertPEMtoPFX(raw key) = %v, want ErrValidation", err)
+	}
+}
@@ -32,7 +32,7 @@ func (c *Client) Logout(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	c.session = ""
+	c.setSession("")
 	return nil
 }
 
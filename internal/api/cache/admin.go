@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+// --- users ---
+
+// ListAdminUsers lists all users, falling back to the saved page.
+func (s *Service) ListAdminUsers(ctx context.Context, page, size int) (*api.PageDTO[api.AdminUser], error) {
+	return readPage(s, "admin-users", page, size, func() (*api.PageDTO[api.AdminUser], error) {
+		return s.Service.ListAdminUsers(ctx, page, size)
+	})
+}
+
+// CountAdminUsers counts all users, falling back to the saved count.
+func (s *Service) CountAdminUsers(ctx context.Context) (int64, error) {
+	return read(s, "admin-users-count", func() (int64, error) {
+		return s.Service.CountAdminUsers(ctx)
+	})
+}
+
+// --- CAs ---
+
+// ListAdminCAs lists the CAs the admin manages, falling back to the saved page.
+func (s *Service) ListAdminCAs(ctx context.Context, page, size int) (*api.PageDTO[api.CACert], error) {
+	return readPage(s, "admin-cas", page, size, func() (*api.PageDTO[api.CACert], error) {
+		return s.Service.ListAdminCAs(ctx, page, size)
+	})
+}
+
+// GetAdminCACert returns a managed CA certificate or chain in PEM, falling back to the saved copy.
+func (s *Service) GetAdminCACert(ctx context.Context, uuid string, chain, needRoot bool) (string, error) {
+	return read(s, certKey("admin-ca", uuid, chain, needRoot), func() (string, error) {
+		return s.Service.GetAdminCACert(ctx, uuid, chain, needRoot)
+	})
+}
+
+// CountAdminCAs counts the CAs the admin manages, falling back to the saved count.
+func (s *Service) CountAdminCAs(ctx context.Context) (int64, error) {
+	return read(s, "admin-cas-count", func() (int64, error) {
+		return s.Service.CountAdminCAs(ctx)
+	})
+}
+
+// UpdateAdminCAComment changes a CA's comment and drops the cached data.
+func (s *Service) UpdateAdminCAComment(ctx context.Context, uuid, comment string) error {
+	return s.write(s.Service.UpdateAdminCAComment(ctx, uuid, comment))
+}
+
+// ToggleAdminCAAvailable enables or disables a CA and drops the cached data.
+func (s *Service) ToggleAdminCAAvailable(ctx context.Context, uuid string, available bool) error {
+	return s.write(s.Service.ToggleAdminCAAvailable(ctx, uuid, available))
+}
+
+// ImportAdminCA imports a CA and drops the cached data.
+func (s *Service) ImportAdminCA(ctx context.Context, req api.ImportCACertRequest) (*api.CACert, error) {
+	ca, err := s.Service.ImportAdminCA(ctx, req)
+	return ca, s.write(err)
+}
+
+// RequestAdminCA requests a CA and drops the cached data.
+func (s *Service) RequestAdminCA(ctx context.Context, req api.RequestCACertRequest) (*api.CACert, error) {
+	ca, err := s.Service.RequestAdminCA(ctx, req)
+	return ca, s.write(err)
+}
+
+// RenewAdminCA renews a CA and drops the cached data.
+func (s *Service) RenewAdminCA(ctx context.Context, uuid string, req api.RenewCACertRequest) (*api.CACert, error) {
+	ca, err := s.Service.RenewAdminCA(ctx, uuid, req)
+	return ca, s.write(err)
+}
+
+// DeleteAdminCA deletes a CA and drops the cached data.
+func (s *Service) DeleteAdminCA(ctx context.Context, uuid string) error {
+	return s.write(s.Service.DeleteAdminCA(ctx, uuid))
+}
+
+// --- bindings ---
+
+// BindUsersToCA binds users to a CA and drops the cached data.
+func (s *Service) BindUsersToCA(ctx context.Context, caUUID string, usernames []string) error {
+	return s.write(s.Service.BindUsersToCA(ctx, caUUID, usernames))
+}
+
+// UnbindUsersFromCA unbinds users from a CA and drops the cached data.
+func (s *Service) UnbindUsersFromCA(ctx context.Context, caUUID string, usernames []string) error {
+	return s.write(s.Service.UnbindUsersFromCA(ctx, caUUID, usernames))
+}
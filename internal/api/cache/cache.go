@@ -0,0 +1,277 @@
+// Package cache keeps the last responses of a CertVault server on disk so
+// that certificates can still be looked up while the server is unreachable.
+//
+// Service wraps an api.Service. While the server answers, reads go to it and
+// their results are saved; when it cannot be reached, reads are answered
+// from the saved results instead and the Service reports itself offline.
+// Writes are never cached: they fail with ErrOffline while the server is
+// unreachable, and a successful write drops the cached data of every user of
+// the server, as a change by one user, e.g. an admin binding a CA, shows in
+// the lists of others. So nothing older than a change made through cvx is
+// ever shown. Private keys are never saved.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/config"
+)
+
+// DefaultTTL is how long saved responses remain usable when New is given no TTL.
+const DefaultTTL = 7 * 24 * time.Hour
+
+// ErrOffline is returned by writes while the server is unreachable.
+var ErrOffline = errors.New("server unreachable; offline mode is read-only")
+
+// DefaultDir returns the directory the cache is kept in.
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, config.ConfigDirName, "cache")
+}
+
+// Service is an api.Service answering reads from disk while the server is
+// unreachable. Responses are kept per server and user. It is safe for
+// concurrent use.
+type Service struct {
+	api.Service
+	dir string
+	ttl time.Duration
+
+	// mu guards the fields below.
+	mu       sync.Mutex
+	session  string // the session username belongs to
+	username string
+	offline  bool
+	saved    time.Time // when the data last served offline was saved
+	hooks    []func(saved time.Time)
+}
+
+var _ api.Service = (*Service)(nil)
+
+// New returns a Service caching the responses of next in dir. Saved
+// responses older than ttl are not used; a ttl of 0 means DefaultTTL.
+func New(next api.Service, dir string, ttl time.Duration) *Service {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Service{Service: next, dir: dir, ttl: ttl}
+}
+
+// Offline reports whether the last read was answered from the cache because
+// the server was unreachable, and when that data was saved.
+func (s *Service) Offline() (saved time.Time, offline bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.saved, s.offline
+}
+
+// OnOffline registers fn to be called with the time the data was saved
+// whenever a read is answered from the cache.
+func (s *Service) OnOffline(fn func(saved time.Time)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, fn)
+}
+
+// setOnline records that the server answered.
+func (s *Service) setOnline() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offline = false
+}
+
+// setOffline records that a read was answered with data saved at saved.
+func (s *Service) setOffline(saved time.Time) {
+	s.mu.Lock()
+	s.offline, s.saved = true, saved
+	hooks := s.hooks
+	s.mu.Unlock()
+	for _, fn := range hooks {
+		fn(saved)
+	}
+}
+
+// serverDir is the directory of the current server.
+func (s *Service) serverDir() string {
+	server := strings.TrimSuffix(s.GetBaseURL(), "/")
+	return filepath.Join(s.dir, digest(server))
+}
+
+// userDir is the directory of the current user, or "" when the user of the
+// session is not known and nothing can be cached.
+func (s *Service) userDir() string {
+	session := s.GetSession()
+	if session == "" {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if session != s.session {
+		var index map[string]string
+		if _, ok := load(filepath.Join(s.serverDir(), sessionsFile), 0, &index); ok {
+			s.session, s.username = session, index[digest(session)]
+		} else {
+			s.session, s.username = session, ""
+		}
+	}
+	if s.username == "" {
+		return ""
+	}
+	return filepath.Join(s.serverDir(), digest(s.username))
+}
+
+// sessionsFile, kept per server, maps session digests to usernames so that
+// the user is known without asking the server.
+const sessionsFile = "sessions.json"
+
+// remember records that the current session belongs to username.
+func (s *Service) remember(username string) {
+	session := s.GetSession()
+	if session == "" || username == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if session == s.session && username == s.username {
+		return
+	}
+	s.session, s.username = session, username
+	s.updateIndex(func(index map[string]string) { index[digest(session)] = username })
+}
+
+// forget drops the current session from the index, e.g. after logging out.
+func (s *Service) forget(session string) {
+	if session == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.session, s.username = "", ""
+	s.updateIndex(func(index map[string]string) { delete(index, digest(session)) })
+}
+
+// updateIndex applies change to the session index; s.mu must be held.
+func (s *Service) updateIndex(change func(map[string]string)) {
+	path := filepath.Join(s.serverDir(), sessionsFile)
+	index := map[string]string{}
+	load(path, 0, &index)
+	change(index)
+	_ = store(path, index)
+}
+
+// read calls fetch and saves its result under key. If the server cannot be
+// reached, the result saved earlier is returned instead.
+func read[T any](s *Service, key string, fetch func() (T, error)) (T, error) {
+	v, err := fetch()
+	if err == nil {
+		s.setOnline()
+		if dir := s.userDir(); dir != "" {
+			_ = store(filepath.Join(dir, key+".json"), v)
+		}
+		return v, nil
+	}
+	if !api.Unreachable(err) {
+		return v, err
+	}
+	dir := s.userDir()
+	if dir == "" {
+		return v, err
+	}
+	var cached T
+	saved, ok := load(filepath.Join(dir, key+".json"), s.ttl, &cached)
+	if !ok {
+		return v, err
+	}
+	s.setOffline(saved)
+	return cached, nil
+}
+
+// readPage is read for a page of a list. Offline, a page that was not saved
+// itself is cut from a saved page of another size that covers it, so that
+// the TUI and the CLI, which use different page sizes, share their lists.
+func readPage[T any](s *Service, name string, page, size int, fetch func() (*api.PageDTO[T], error)) (*api.PageDTO[T], error) {
+	p, err := read(s, pageKey(name, page, size), fetch)
+	if err == nil || !api.Unreachable(err) {
+		return p, err
+	}
+	dir := s.userDir()
+	if dir == "" {
+		return p, err
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, name+"-*-*.json"))
+	start := (page - 1) * size
+	for _, path := range paths {
+		var savedPage, savedSize int
+		if _, err := fmt.Sscanf(filepath.Base(path), name+"-%d-%d.json", &savedPage, &savedSize); err != nil {
+			continue
+		}
+		var cached api.PageDTO[T]
+		saved, ok := load(path, s.ttl, &cached)
+		if !ok {
+			continue
+		}
+		offset := (savedPage - 1) * savedSize
+		from := start - offset
+		to := max(from, min(from+size, int(cached.Total)-offset))
+		if from < 0 || to > len(cached.List) {
+			continue
+		}
+		s.setOffline(saved)
+		return &api.PageDTO[T]{Total: cached.Total, List: cached.List[from:to]}, nil
+	}
+	return p, err
+}
+
+// write finishes a request changing the server. A successful change drops
+// the server's cached data, which may no longer be accurate.
+func (s *Service) write(err error) error {
+	if err == nil {
+		s.setOnline()
+		s.invalidate()
+		return nil
+	}
+	if api.Unreachable(err) {
+		return fmt.Errorf("%w: %w", ErrOffline, err)
+	}
+	return err
+}
+
+// invalidate removes the cached data of every user of the current server.
+// The session index stays, as it is not derived from the server's data.
+func (s *Service) invalidate() {
+	dir := s.serverDir()
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if e.Name() != sessionsFile {
+			_ = os.RemoveAll(filepath.Join(dir, e.Name()))
+		}
+	}
+}
+
+// pageKey names a cached page of a list.
+func pageKey(name string, page, size int) string {
+	return fmt.Sprintf("%s-%d-%d", name, page, size)
+}
+
+// certKey names a cached certificate in PEM.
+func certKey(name, uuid string, chain, needRoot bool) string {
+	return fmt.Sprintf("%s-%s-%t-%t", name, digest(uuid), chain, needRoot)
+}
+
+// digest turns s into a file name.
+func digest(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"slices"
+	"syscall"
+	"testing"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api/fake"
+)
+
+// errDown is what api.Client returns while the server is down.
+var errDown = &url.Error{Op: "Get", URL: "http://certvault.test", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+
+// switchable is a fake server whose lists can be made unreachable.
+type switchable struct {
+	*fake.Service
+	down bool
+}
+
+func (s *switchable) ListAdminUsers(ctx context.Context, page, size int) (*api.PageDTO[api.AdminUser], error) {
+	if s.down {
+		return nil, errDown
+	}
+	return s.Service.ListAdminUsers(ctx, page, size)
+}
+
+func (s *switchable) ListUserSSLCerts(ctx context.Context, page, size int) (*api.PageDTO[api.SSLCert], error) {
+	if s.down {
+		return nil, errDown
+	}
+	return s.Service.ListUserSSLCerts(ctx, page, size)
+}
+
+// login returns a cache in dir on a Service of store logged in as username.
+func login(t *testing.T, store *fake.Store, dir, username string) (*Service, *switchable) {
+	t.Helper()
+	next := &switchable{Service: store.Client()}
+	s := New(next, dir, 0)
+	if err := s.Login(context.Background(), username, username); err != nil {
+		t.Fatal(err)
+	}
+	return s, next
+}
+
+// seeded returns a Store with 7 users: the demo users and user1 to user3.
+func seeded(t *testing.T) *fake.Store {
+	t.Helper()
+	store := fake.NewStore()
+	if err := store.Seed(); err != nil {
+		t.Fatal(err)
+	}
+	sa := store.Client()
+	ctx := context.Background()
+	if err := sa.Login(ctx, fake.DefaultUsername, fake.DefaultPassword); err != nil {
+		t.Fatal(err)
+	}
+	var users []api.CreateUserRequest
+	for _, name := range []string{"user1", "user2", "user3"} {
+		users = append(users, api.CreateUserRequest{Username: name, DisplayName: name, Email: name + "@example.test", Password: name, Role: fake.RoleUser})
+	}
+	if err := sa.BatchCreateUsers(ctx, users); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func usernames(users []api.AdminUser) []string {
+	var names []string
+	for _, u := range users {
+		names = append(names, u.Username)
+	}
+	return names
+}
+
+func TestReadPage(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		savedPage int // the page saved while online
+		savedSize int
+		page      int // the page requested offline
+		size      int
+		wantFrom  int // the expected users, as indexes into the whole list
+		wantTo    int
+		wantErr   bool
+	}{
+		{name: "same page", savedPage: 1, savedSize: 10, page: 1, size: 10, wantFrom: 0, wantTo: 7},
+		{name: "first of smaller pages", savedPage: 1, savedSize: 10, page: 1, size: 3, wantFrom: 0, wantTo: 3},
+		{name: "middle of smaller pages", savedPage: 1, savedSize: 10, page: 2, size: 3, wantFrom: 3, wantTo: 6},
+		{name: "last, partial page", savedPage: 1, savedSize: 10, page: 3, size: 3, wantFrom: 6, wantTo: 7},
+		{name: "inside a later saved page", savedPage: 2, savedSize: 3, page: 4, size: 1, wantFrom: 3, wantTo: 4},
+		{name: "end of a later saved page", savedPage: 2, savedSize: 5, page: 4, size: 2, wantFrom: 6, wantTo: 7},
+		{name: "starts before the saved page", savedPage: 2, savedSize: 5, page: 3, size: 2, wantErr: true},
+		{name: "ends after the saved page", savedPage: 1, savedSize: 3, page: 2, size: 2, wantErr: true},
+		{name: "larger than the saved page", savedPage: 1, savedSize: 3, page: 1, size: 10, wantErr: true},
+		{name: "nothing saved", savedPage: 0, page: 1, size: 10, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := seeded(t)
+			s, next := login(t, store, t.TempDir(), "admin")
+			all, err := next.Service.ListAdminUsers(ctx, 1, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(all.List) != 7 {
+				t.Fatalf("seeded %d users, want 7", len(all.List))
+			}
+			if tt.savedPage > 0 {
+				if _, err := s.ListAdminUsers(ctx, tt.savedPage, tt.savedSize); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			next.down = true
+			got, err := s.ListAdminUsers(ctx, tt.page, tt.size)
+			if tt.wantErr {
+				if !errors.Is(err, errDown) {
+					t.Fatalf("ListAdminUsers() = %v, %v; want the unreachable error", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := usernames(all.List[tt.wantFrom:tt.wantTo]); !slices.Equal(usernames(got.List), want) {
+				t.Errorf("ListAdminUsers() = %v, want %v", usernames(got.List), want)
+			}
+			if got.Total != all.Total {
+				t.Errorf("Total = %d, want %d", got.Total, all.Total)
+			}
+			if _, offline := s.Offline(); !offline {
+				t.Error("Offline() = false after answering from the cache")
+			}
+		})
+	}
+}
+
+func TestWriteInvalidatesOtherUsers(t *testing.T) {
+	ctx := context.Background()
+	store := seeded(t)
+	dir := t.TempDir()
+	admin, adminNext := login(t, store, dir, "admin")
+	alice, aliceNext := login(t, store, dir, "alice")
+
+	if _, err := admin.ListAdminUsers(ctx, 1, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := alice.ListUserSSLCerts(ctx, 1, 10); err != nil {
+		t.Fatal(err)
+	}
+	cas, err := admin.ListAdminCAs(ctx, 1, 10)
+	if err != nil || len(cas.List) == 0 {
+		t.Fatalf("ListAdminCAs() = %v, %v", cas, err)
+	}
+	if err := admin.BindUsersToCA(ctx, cas.List[0].UUID, []string{"user1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	adminNext.down, aliceNext.down = true, true
+	if _, err := admin.ListAdminUsers(ctx, 1, 10); !errors.Is(err, errDown) {
+		t.Errorf("admin's list after the change: err = %v, want the unreachable error", err)
+	}
+	if _, err := alice.ListUserSSLCerts(ctx, 1, 10); !errors.Is(err, errDown) {
+		t.Errorf("alice's list after admin's change: err = %v, want the unreachable error", err)
+	}
+
+	// The session index survives, so new data is cached again.
+	adminNext.down = false
+	if _, err := admin.ListAdminUsers(ctx, 1, 10); err != nil {
+		t.Fatal(err)
+	}
+	adminNext.down = true
+	if _, err := admin.ListAdminUsers(ctx, 1, 10); err != nil {
+		t.Errorf("admin's list saved after the change: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+// --- sessions ---
+
+// ForceLogoutUser ends every session of a user.
+func (s *Service) ForceLogoutUser(ctx context.Context, username string) error {
+	return s.write(s.Service.ForceLogoutUser(ctx, username))
+}
+
+// --- users ---
+
+// CreateUser creates a user and drops the cached data.
+func (s *Service) CreateUser(ctx context.Context, req api.CreateUserRequest) (*api.AdminUser, error) {
+	u, err := s.Service.CreateUser(ctx, req)
+	return u, s.write(err)
+}
+
+// BatchCreateUsers creates several users and drops the cached data.
+func (s *Service) BatchCreateUsers(ctx context.Context, users []api.CreateUserRequest) error {
+	return s.write(s.Service.BatchCreateUsers(ctx, users))
+}
+
+// BatchDeleteUsers deletes several users and drops the cached data.
+func (s *Service) BatchDeleteUsers(ctx context.Context, usernames []string) error {
+	return s.write(s.Service.BatchDeleteUsers(ctx, usernames))
+}
+
+// UpdateSuperadminUser updates a user and drops the cached data.
+func (s *Service) UpdateSuperadminUser(ctx context.Context, username string, req api.UpdateSuperadminUserRequest) error {
+	return s.write(s.Service.UpdateSuperadminUser(ctx, username, req))
+}
+
+// UpdateUserRole changes a user's role and drops the cached data.
+func (s *Service) UpdateUserRole(ctx context.Context, req api.UpdateUserRoleRequest) error {
+	return s.write(s.Service.UpdateUserRole(ctx, req))
+}
+
+// DeleteSuperadminUser deletes a user and drops the cached data.
+func (s *Service) DeleteSuperadminUser(ctx context.Context, username string) error {
+	return s.write(s.Service.DeleteSuperadminUser(ctx, username))
+}
+
+// --- statistics ---
+
+// CountAllSSLCerts counts every certificate, falling back to the saved count.
+func (s *Service) CountAllSSLCerts(ctx context.Context) (int64, error) {
+	return read(s, "all-certs-count", func() (int64, error) {
+		return s.Service.CountAllSSLCerts(ctx)
+	})
+}
+
+// CountAllCAs counts every CA, falling back to the saved count.
+func (s *Service) CountAllCAs(ctx context.Context) (int64, error) {
+	return read(s, "all-cas-count", func() (int64, error) {
+		return s.Service.CountAllCAs(ctx)
+	})
+}
@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+// --- login and profile ---
+
+// Login logs in and records which user the new session belongs to.
+func (s *Service) Login(ctx context.Context, username, password string) error {
+	if err := s.Service.Login(ctx, username, password); err != nil {
+		return err
+	}
+	s.setOnline()
+	s.remember(username)
+	return nil
+}
+
+// Logout logs out and forgets the session.
+func (s *Service) Logout(ctx context.Context) error {
+	session := s.GetSession()
+	if err := s.Service.Logout(ctx); err != nil {
+		return err
+	}
+	s.forget(session)
+	return nil
+}
+
+// LogoutAllSessions ends every session of the user and forgets this one.
+func (s *Service) LogoutAllSessions(ctx context.Context) error {
+	session := s.GetSession()
+	if err := s.write(s.Service.LogoutAllSessions(ctx)); err != nil {
+		return err
+	}
+	s.forget(session)
+	return nil
+}
+
+// GetProfile returns the user's profile, which also tells which user the
+// session belongs to.
+func (s *Service) GetProfile(ctx context.Context) (*api.UserProfile, error) {
+	return read(s, "profile", func() (*api.UserProfile, error) {
+		p, err := s.Service.GetProfile(ctx)
+		if err == nil {
+			s.remember(p.Username)
+		}
+		return p, err
+	})
+}
+
+// UpdateProfile updates the profile and drops the cached data.
+func (s *Service) UpdateProfile(ctx context.Context, req api.UpdateProfileRequest) error {
+	return s.write(s.Service.UpdateProfile(ctx, req))
+}
+
+// LogoutSession ends another session of the user.
+func (s *Service) LogoutSession(ctx context.Context, uuid string) error {
+	return s.write(s.Service.LogoutSession(ctx, uuid))
+}
+
+// --- CAs ---
+
+// ListUserCAs lists the user's CAs, falling back to the saved page.
+func (s *Service) ListUserCAs(ctx context.Context, page, size int) (*api.PageDTO[api.CACert], error) {
+	return readPage(s, "user-cas", page, size, func() (*api.PageDTO[api.CACert], error) {
+		return s.Service.ListUserCAs(ctx, page, size)
+	})
+}
+
+// GetUserCACert returns a CA certificate or chain in PEM, falling back to the saved copy.
+func (s *Service) GetUserCACert(ctx context.Context, uuid string, chain, needRoot bool) (string, error) {
+	return read(s, certKey("user-ca", uuid, chain, needRoot), func() (string, error) {
+		return s.Service.GetUserCACert(ctx, uuid, chain, needRoot)
+	})
+}
+
+// CountUserCAs counts the user's CAs, falling back to the saved count.
+func (s *Service) CountUserCAs(ctx context.Context) (int64, error) {
+	return read(s, "user-cas-count", func() (int64, error) {
+		return s.Service.CountUserCAs(ctx)
+	})
+}
+
+// --- SSL certificates ---
+
+// ListUserSSLCerts lists the user's certificates, falling back to the saved page.
+func (s *Service) ListUserSSLCerts(ctx context.Context, page, size int) (*api.PageDTO[api.SSLCert], error) {
+	return readPage(s, "user-certs", page, size, func() (*api.PageDTO[api.SSLCert], error) {
+		return s.Service.ListUserSSLCerts(ctx, page, size)
+	})
+}
+
+// GetUserSSLCert returns a certificate or chain in PEM, falling back to the saved copy.
+func (s *Service) GetUserSSLCert(ctx context.Context, uuid string, chain, needRoot bool) (string, error) {
+	return read(s, certKey("user-cert", uuid, chain, needRoot), func() (string, error) {
+		return s.Service.GetUserSSLCert(ctx, uuid, chain, needRoot)
+	})
+}
+
+// CountUserSSLCerts counts the user's certificates, falling back to the saved count.
+func (s *Service) CountUserSSLCerts(ctx context.Context) (int64, error) {
+	return read(s, "user-certs-count", func() (int64, error) {
+		return s.Service.CountUserSSLCerts(ctx)
+	})
+}
+
+// RequestSSLCert requests a certificate and drops the cached data.
+func (s *Service) RequestSSLCert(ctx context.Context, req api.RequestSSLCertRequest) (*api.SSLCert, error) {
+	cert, err := s.Service.RequestSSLCert(ctx, req)
+	return cert, s.write(err)
+}
+
+// RenewSSLCert renews a certificate and drops the cached data.
+func (s *Service) RenewSSLCert(ctx context.Context, uuid string, req api.RenewSSLCertRequest) (*api.SSLCert, error) {
+	cert, err := s.Service.RenewSSLCert(ctx, uuid, req)
+	return cert, s.write(err)
+}
+
+// DeleteSSLCert deletes a certificate and drops the cached data.
+func (s *Service) DeleteSSLCert(ctx context.Context, uuid string) error {
+	return s.write(s.Service.DeleteSSLCert(ctx, uuid))
+}
+
+// UpdateSSLCertComment changes a certificate's comment and drops the cached data.
+func (s *Service) UpdateSSLCertComment(ctx context.Context, uuid, comment string) error {
+	return s.write(s.Service.UpdateSSLCertComment(ctx, uuid, comment))
+}
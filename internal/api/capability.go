@@ -0,0 +1,206 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Capability is an optional part of the CertVault API that older servers
+// lack.
+type Capability string
+
+const (
+	// CapStats is the /count endpoints behind the dashboard statistics.
+	CapStats Capability = "stats"
+	// CapSessions is listing and revoking login sessions.
+	CapSessions Capability = "sessions"
+	// CapCABindings is listing the users bound, or not bound, to a CA.
+	CapCABindings Capability = "ca-bindings"
+	// CapTools is the certificate and key analysis and conversion endpoints.
+	CapTools Capability = "tools"
+)
+
+// capabilityInfo describes a capability and how to probe for it. A probe
+// must not change anything on the server.
+type capabilityInfo struct {
+	desc   string
+	method string
+	path   string
+	body   any
+	// match reports whether an endpoint path belongs to the capability.
+	match func(path string) bool
+}
+
+var capabilities = map[Capability]capabilityInfo{
+	CapStats: {
+		desc:   "Statistics",
+		method: http.MethodGet,
+		path:   "/api/v1/user/cert/ca/count",
+		match:  func(p string) bool { return strings.HasSuffix(p, "/count") },
+	},
+	CapSessions: {
+		desc:   "Session management",
+		method: http.MethodGet,
+		path:   "/api/v1/user/session?page=1&limit=1",
+		match: func(p string) bool {
+			return strings.HasPrefix(p, "/api/v1/user/session") || strings.HasPrefix(p, "/api/v1/superadmin/user/session")
+		},
+	},
+	CapCABindings: {
+		desc:   "Listing the users of a CA",
+		method: http.MethodGet,
+		// A CA that cannot exist: a server with the endpoint answers "not
+		// found" or "forbidden" in the envelope.
+		path: "/api/v1/admin/cert/ca/00000000-0000-0000-0000-000000000000/bind?page=1&limit=1",
+		match: func(p string) bool {
+			return strings.HasPrefix(p, "/api/v1/admin/cert/ca/") && (strings.HasSuffix(p, "/bind") || strings.HasSuffix(p, "/bind/not"))
+		},
+	},
+	CapTools: {
+		desc:   "Certificate analysis and conversion",
+		method: http.MethodPost,
+		path:   "/api/v1/user/cert/analyze",
+		body:   AnalyzeCertRequest{},
+		match: func(p string) bool {
+			return strings.HasSuffix(p, "/analyze") || strings.HasPrefix(p, "/api/v1/user/cert/convert/")
+		},
+	},
+}
+
+// AllCapabilities lists every capability.
+func AllCapabilities() []Capability {
+	return []Capability{CapStats, CapSessions, CapCABindings, CapTools}
+}
+
+// String returns the name of c, as used by --without of cvx dev mock-server.
+func (c Capability) String() string {
+	return string(c)
+}
+
+// Description describes c for messages, e.g. "Statistics".
+func (c Capability) Description() string {
+	if info, ok := capabilities[c]; ok {
+		return info.desc
+	}
+	return string(c)
+}
+
+// Matches reports whether the endpoint at path belongs to c.
+func (c Capability) Matches(path string) bool {
+	info, ok := capabilities[c]
+	return ok && info.match(path)
+}
+
+// capabilityOf returns the capability the endpoint at path belongs to.
+func capabilityOf(path string) (Capability, bool) {
+	for _, c := range AllCapabilities() {
+		if c.Matches(path) {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// Capabilities is the set of optional features a server supports. The zero
+// value supports all of them, which is what cvx assumes until it probes.
+type Capabilities struct {
+	missing []Capability
+}
+
+// NewCapabilities returns the capabilities of a server that lacks missing.
+func NewCapabilities(missing ...Capability) Capabilities {
+	missing = slices.Clone(missing)
+	slices.Sort(missing)
+	return Capabilities{missing: slices.Compact(missing)}
+}
+
+// Has reports whether the server supports c.
+func (s Capabilities) Has(c Capability) bool {
+	return !slices.Contains(s.missing, c)
+}
+
+// Missing returns the capabilities the server lacks.
+func (s Capabilities) Missing() []Capability {
+	return slices.Clone(s.missing)
+}
+
+// Check returns an error explaining that the server lacks c, or nil if it
+// has it. The error matches ErrUnsupported.
+func (s Capabilities) Check(c Capability) error {
+	if s.Has(c) {
+		return nil
+	}
+	return &UnsupportedError{Capability: c}
+}
+
+// UnsupportedError reports a capability the server lacks.
+type UnsupportedError struct {
+	Capability Capability
+}
+
+func (e *UnsupportedError) Error() string {
+	return e.Capability.Description() + " is not supported by this CertVault server; it needs a newer server version"
+}
+
+// Is matches ErrUnsupported.
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupported
+}
+
+// missingEndpoint reports whether err, the answer to a probe, shows that the
+// endpoint does not exist on the server. CertVault reports errors of existing
+// endpoints in the envelope with HTTP 200, so an HTTP 404 or 405 to a probe
+// comes from the router. Outside probes an HTTP 404 stays ErrNotFound, as a
+// proxy or a missing resource may answer with it too.
+func missingEndpoint(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.notJSON || apiErr.Status == http.StatusNotFound || apiErr.Status == http.StatusMethodNotAllowed
+}
+
+// Probe asks the server which of caps, or of all capabilities if none are
+// given, it supports. A probe that fails for any other reason than a missing
+// endpoint, e.g. because the server is unreachable, counts as supported so
+// that the request itself reports the problem later.
+func (c *Client) Probe(ctx context.Context, caps ...Capability) Capabilities {
+	if len(caps) == 0 {
+		caps = AllCapabilities()
+	}
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		missing []Capability
+	)
+	for _, want := range caps {
+		info, ok := capabilities[want]
+		if !ok {
+			continue
+		}
+		wg.Go(func() {
+			// One attempt: a probe that fails for another reason counts as
+			// supported anyway, and the request that follows retries.
+			var data []byte
+			if info.body != nil {
+				data, _ = json.Marshal(info.body)
+			}
+			resp, err := c.send(ctx, info.method, info.path, data, info.body != nil)
+			if err == nil {
+				_, err = decodeResponse[any](resp)
+			}
+			if missingEndpoint(err) {
+				mu.Lock()
+				missing = append(missing, want)
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	return NewCapabilities(missing...)
+}
@@ -3,60 +3,146 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/cookiejar"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gregPerlinLi/CertVaultCLIX/internal/version"
 	"golang.org/x/net/publicsuffix"
 )
 
-// ErrUnauthorized is returned when the server returns HTTP 401 or API code 401,
-// indicating the session has expired and the user must log in again.
-var ErrUnauthorized = errors.New("session expired: please log in again")
-
 const defaultTimeout = 30 * time.Second
 
-// Client is the CertVault API HTTP client.
+// Client is the CertVault API HTTP client. It is safe for concurrent use.
 type Client struct {
-	baseURL    string
 	httpClient *http.Client
-	session    string
+	retry      RetryPolicy
+
+	// mu guards the fields below; they change while requests run in other
+	// goroutines.
+	mu           sync.Mutex
+	baseURL      string
+	session      string
+	headers      http.Header
+	traceHooks   []traceHook
+	sessionHooks []func(string)
+
+	// notifyMu orders the calls of the session hooks.
+	notifyMu sync.Mutex
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithTimeout sets the timeout of a single request attempt.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		if d > 0 {
+			c.httpClient.Timeout = d
+		}
+	}
+}
+
+// WithRetry sets the retry policy for idempotent requests.
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// WithTLSConfig sets the TLS configuration used for HTTPS servers,
+// typically built with TLSOptions.Config.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) { c.transport().TLSClientConfig = cfg }
 }
 
 // NewClient creates a new API client.
-func NewClient(baseURL string) *Client {
+func NewClient(baseURL string, opts ...Option) *Client {
 	jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
-	return &Client{
+	c := &Client{
 		baseURL: baseURL,
 		httpClient: &http.Client{
 			Timeout: defaultTimeout,
 			Jar:     jar,
 		},
+		retry: DefaultRetryPolicy,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // SetSession sets the JSESSIONID cookie on the client.
 func (c *Client) SetSession(session string) {
-	c.session = session
+	c.setSession(session)
 }
 
 // GetSession returns the current JSESSIONID value.
 func (c *Client) GetSession() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.session
 }
 
+// OnSessionChange registers fn to be called with the new session whenever
+// it changes: when the server sets a JSESSIONID cookie, on logout, or
+// through SetSession. fn runs in the goroutine of the change. Calls are never
+// concurrent, and when changes race the last call has the latest session, so
+// fn may simply save what it gets. fn must not change the session itself.
+func (c *Client) OnSessionChange(fn func(session string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessionHooks = append(c.sessionHooks, fn)
+}
+
+// setSession stores session and calls the session hooks if it changed.
+func (c *Client) setSession(session string) {
+	c.mu.Lock()
+	if c.session == session {
+		c.mu.Unlock()
+		return
+	}
+	c.session = session
+	c.mu.Unlock()
+	c.notifySession()
+}
+
+// notifySession calls the session hooks with the current session. The
+// session is read only once notifyMu is held, so a hook call delayed behind
+// another change still reports the session that is current, not its own.
+func (c *Client) notifySession() {
+	c.notifyMu.Lock()
+	defer c.notifyMu.Unlock()
+	c.mu.Lock()
+	session, hooks := c.session, c.sessionHooks
+	c.mu.Unlock()
+	for _, fn := range hooks {
+		fn(session)
+	}
+}
+
 // SetBaseURL updates the base URL.
 func (c *Client) SetBaseURL(url string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.baseURL = url
 }
 
+// InsecureTLS reports whether server certificates are not verified.
+func (c *Client) InsecureTLS() bool {
+	t, ok := c.httpClient.Transport.(*http.Transport)
+	return ok && t.TLSClientConfig != nil && t.TLSClientConfig.InsecureSkipVerify
+}
+
 // GetBaseURL returns the base URL.
 func (c *Client) GetBaseURL() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.baseURL
 }
 
@@ -64,30 +150,71 @@ func (c *Client) userAgent() string {
 	return fmt.Sprintf("Mozilla/5.0 (Linux) CertVaultCLIX/%s", version.Version)
 }
 
+// do sends a request. Idempotent requests are retried according to the
+// client's RetryPolicy; all others are sent exactly once.
 func (c *Client) do(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
-	var bodyReader io.Reader
+	var data []byte
 	if body != nil {
-		data, err := json.Marshal(body)
-		if err != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, path, data, body != nil)
+		if !idempotent(method) {
+			return resp, err
+		}
+		delay, retry := c.retry.nextDelay(ctx, attempt, resp, err)
+		if !retry {
+			return resp, err
+		}
+		if resp != nil {
+			discard(resp)
+		}
+		if err := sleep(ctx, delay); err != nil {
 			return nil, err
 		}
+	}
+}
+
+// send makes a single request attempt.
+func (c *Client) send(ctx context.Context, method, path string, data []byte, hasBody bool) (*http.Response, error) {
+	var bodyReader io.Reader
+	if hasBody {
 		bodyReader = bytes.NewReader(data)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
+	c.mu.Lock()
+	baseURL, headers, session := c.baseURL, c.headers, c.session
+	c.mu.Unlock()
+
+	target, err := joinURL(baseURL, path)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bodyReader)
 	if err != nil {
 		return nil, err
 	}
 
+	for name, values := range headers {
+		req.Header[name] = values
+	}
 	req.Header.Set("User-Agent", c.userAgent())
-	if body != nil {
+	if hasBody {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	if c.session != "" {
-		req.AddCookie(&http.Cookie{Name: "JSESSIONID", Value: c.session})
+	if session != "" {
+		req.AddCookie(&http.Cookie{Name: "JSESSIONID", Value: session})
 	}
 
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
+	if hooks := c.traceHooksCopy(); len(hooks) > 0 {
+		c.trace(hooks, req, data, resp, err, start)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -95,7 +222,7 @@ func (c *Client) do(ctx context.Context, method, path string, body interface{})
 	// Extract JSESSIONID from response cookies
 	for _, cookie := range resp.Cookies() {
 		if cookie.Name == "JSESSIONID" {
-			c.session = cookie.Value
+			c.setSession(cookie.Value)
 		}
 	}
 
@@ -122,12 +249,13 @@ func (c *Client) delete(ctx context.Context, path string) (*http.Response, error
 	return c.do(ctx, http.MethodDelete, path, nil)
 }
 
-// decodeResponse decodes a JSON response into the given type.
+// decodeResponse decodes a JSON response into the given type. Error
+// responses are returned as *APIError.
 func decodeResponse[T any](resp *http.Response) (*ResultVO[T], error) {
 	defer resp.Body.Close()
 	// Treat HTTP 401 as session-expired regardless of body
 	if resp.StatusCode == http.StatusUnauthorized {
-		return nil, ErrUnauthorized
+		return nil, newAPIError[T](resp, nil)
 	}
 	// HTTP 204 No Content — return a zero-value success result (e.g. empty PageDTO).
 	// Code is normalised to 200 so all callers can treat it uniformly as a success.
@@ -137,11 +265,19 @@ func decodeResponse[T any](resp *http.Response) (*ResultVO[T], error) {
 	}
 	var result ResultVO[T]
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		if resp.StatusCode >= 400 {
+			return nil, newAPIError[T](resp, nil)
+		}
+		if !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+			e := newAPIError[T](resp, nil)
+			e.notJSON = true
+			return nil, e
+		}
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 	// Some API implementations return 401 in the body code field
 	if result.Code == 401 {
-		return nil, ErrUnauthorized
+		return nil, newAPIError(resp, &result)
 	}
 	// Some API implementations return 204 in the body code field for empty results.
 	// Treat as success with zero-value data.
@@ -149,8 +285,8 @@ func decodeResponse[T any](resp *http.Response) (*ResultVO[T], error) {
 		var zero T
 		return &ResultVO[T]{Code: 200, Data: zero}, nil
 	}
-	if result.Code != 200 && result.Code != 0 {
-		return &result, fmt.Errorf("API error %d: %s", result.Code, result.Msg)
+	if (result.Code != 200 && result.Code != 0) || resp.StatusCode >= 400 {
+		return &result, newAPIError(resp, &result)
 	}
 	return &result, nil
 }
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSessionHooksSeeLatestSession(t *testing.T) {
+	for range 50 {
+		c := NewClient("http://localhost")
+		var (
+			mu    sync.Mutex
+			saved string
+		)
+		c.OnSessionChange(func(session string) {
+			// Like saving a file, the hook takes a while.
+			time.Sleep(time.Duration(rand.IntN(500)) * time.Microsecond)
+			mu.Lock()
+			defer mu.Unlock()
+			saved = session
+		})
+
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Go(func() { c.SetSession(fmt.Sprintf("s%d", i)) })
+		}
+		wg.Wait()
+
+		if got, want := saved, c.GetSession(); got != want {
+			t.Fatalf("last hook call saved %q, session is %q", got, want)
+		}
+	}
+}
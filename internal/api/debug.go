@@ -0,0 +1,328 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxTraceBody caps the body size kept in a Trace.
+const maxTraceBody = 16 << 10
+
+// Redacted replaces secrets in traces.
+const Redacted = "[REDACTED]"
+
+// Trace records one request attempt with its secrets redacted. A summary
+// trace, see Client.OnTraceSummary, has no Header, Request and Response.
+type Trace struct {
+	Time    time.Time
+	Method  string
+	URL     string
+	Status  int // 0 if no response was received
+	Latency time.Duration
+	// Header holds the request headers; Cookie and custom header values are redacted.
+	Header http.Header
+	// Request and Response are the bodies, redacted and truncated.
+	Request  string
+	Response string
+	// Err is the transport error, if any.
+	Err string
+}
+
+// Summary renders t on one line, e.g. "GET /api/v1/user/profile → 200 (35ms)".
+func (t Trace) Summary() string {
+	path := t.URL
+	if i := strings.Index(path, "://"); i >= 0 {
+		if j := strings.Index(path[i+3:], "/"); j >= 0 {
+			path = path[i+3+j:]
+		}
+	}
+	result := fmt.Sprint(t.Status)
+	if t.Err != "" {
+		result = "error"
+	}
+	return fmt.Sprintf("%s %s → %s (%s)", t.Method, path, result, t.Latency.Round(time.Millisecond))
+}
+
+// WithTrace calls fn after every request attempt.
+func WithTrace(fn func(Trace)) Option {
+	return func(c *Client) { c.OnTrace(fn) }
+}
+
+// traceHook is a registered trace hook. detail reports whether fn wants
+// the headers and bodies; nil means always.
+type traceHook struct {
+	fn     func(Trace)
+	detail func() bool
+}
+
+func (h traceHook) wantsDetail() bool {
+	return h.detail == nil || h.detail()
+}
+
+// OnTrace registers fn to be called after every request attempt, in
+// addition to any hook registered before. fn may be called concurrently.
+func (c *Client) OnTrace(fn func(Trace)) {
+	c.OnTraceSummary(fn, nil)
+}
+
+// OnTraceSummary registers fn like OnTrace, but while detail reports false
+// fn only receives summary traces. Reading and redacting the bodies is
+// skipped unless some hook wants them, so a summary hook costs next to
+// nothing on large responses.
+func (c *Client) OnTraceSummary(fn func(Trace), detail func() bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.traceHooks = append(c.traceHooks, traceHook{fn, detail})
+}
+
+// traceHooksCopy returns the registered trace hooks.
+func (c *Client) traceHooksCopy() []traceHook {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.traceHooks
+}
+
+// trace builds the Trace of an attempt and passes it to hooks. When a hook
+// wants the details, the response body is read and replaced so the caller
+// can still decode it.
+func (c *Client) trace(hooks []traceHook, req *http.Request, reqBody []byte, resp *http.Response, err error, start time.Time) {
+	c.mu.Lock()
+	custom := c.headers
+	c.mu.Unlock()
+
+	summary := Trace{
+		Time:    start,
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Latency: time.Since(start),
+	}
+	if err != nil {
+		summary.Err = err.Error()
+	}
+	if resp != nil {
+		summary.Status = resp.StatusCode
+	}
+	detail := make([]bool, len(hooks))
+	for i, h := range hooks {
+		detail[i] = h.wantsDetail()
+	}
+
+	full := summary
+	if slices.Contains(detail, true) {
+		full.Header = redactHeader(req.Header, custom)
+		full.Request = redactBody(req.URL.Path, reqBody)
+		if resp != nil {
+			data, readErr := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(data))
+			if readErr != nil {
+				full.Err = readErr.Error()
+			}
+			full.Response = redactBody(req.URL.Path, data)
+		}
+	}
+	for i, h := range hooks {
+		if detail[i] {
+			h.fn(full)
+		} else {
+			h.fn(summary)
+		}
+	}
+}
+
+// redactHeader copies h, hiding cookie values and the custom headers of the
+// client, which typically carry API keys.
+func redactHeader(h, custom http.Header) http.Header {
+	out := h.Clone()
+	for name := range out {
+		if _, ok := custom[name]; ok || name == "Authorization" {
+			out[name] = []string{Redacted}
+		}
+	}
+	if cookies := out.Values("Cookie"); len(cookies) > 0 {
+		out["Cookie"] = []string{cookieValue.ReplaceAllString(strings.Join(cookies, "; "), "${1}="+Redacted)}
+	}
+	return out
+}
+
+var (
+	cookieValue = regexp.MustCompile(`([^=;\s]+)=[^;]*`)
+	pemKey      = regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`)
+)
+
+// secretFields are JSON field names (lower case) whose values are never logged.
+var secretFields = map[string]bool{
+	"password":    true,
+	"oldpassword": true,
+	"newpassword": true,
+	"privkey":     true,
+	"privatekey":  true,
+}
+
+// secretResponses match the paths whose response data is a private key or
+// a bundle holding one, such as a PFX file, that isPrivateKey cannot spot.
+// They are not anchored at the start as the server URL may add a prefix.
+var secretResponses = []*regexp.Regexp{
+	regexp.MustCompile(`/api/v1/user/cert/ssl/[^/]+/privkey$`),
+	regexp.MustCompile(`/api/v1/admin/cert/ca/[^/]+/privkey$`),
+	regexp.MustCompile(`/api/v1/user/cert/convert/pem/to/pfx$`),
+}
+
+// isSecretResponse reports whether the response data of path is always hidden.
+func isSecretResponse(path string) bool {
+	for _, re := range secretResponses {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactBody returns body with passwords and private keys replaced, and
+// truncated to maxTraceBody. The data of a secretResponses path is always
+// hidden.
+func redactBody(path string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return truncate(pemKey.ReplaceAllString(string(body), Redacted))
+	}
+	if m, ok := v.(map[string]any); ok && isSecretResponse(path) {
+		if _, ok := m["data"]; ok {
+			m["data"] = Redacted
+		}
+	}
+	data, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return ""
+	}
+	return truncate(string(data))
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, field := range v {
+			if secretFields[strings.ToLower(k)] {
+				v[k] = Redacted
+			} else {
+				v[k] = redactValue(field)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	case string:
+		if isPrivateKey(v) {
+			return Redacted
+		}
+	}
+	return v
+}
+
+// isPrivateKey reports whether s is, or contains, a private key in PEM form,
+// possibly base64-encoded as the API transfers PEM data.
+func isPrivateKey(s string) bool {
+	if strings.Contains(s, "PRIVATE KEY-----") {
+		return true
+	}
+	if len(s) < 40 {
+		return false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	return err == nil && bytes.Contains(decoded, []byte("PRIVATE KEY-----"))
+}
+
+func truncate(s string) string {
+	if len(s) <= maxTraceBody {
+		return s
+	}
+	return s[:maxTraceBody] + fmt.Sprintf("… (%d bytes truncated)", len(s)-maxTraceBody)
+}
+
+// TraceRing keeps the most recent traces. It is safe for concurrent use.
+// Registered with Client.OnTraceSummary(r.Add, r.Detailed), it records
+// summaries until SetDetailed is called.
+type TraceRing struct {
+	detailed atomic.Bool
+
+	mu     sync.Mutex
+	traces []Trace
+	next   int
+	full   bool
+}
+
+// NewTraceRing returns a ring holding the last n traces.
+func NewTraceRing(n int) *TraceRing {
+	return &TraceRing{traces: make([]Trace, n)}
+}
+
+// Add records t, dropping the oldest trace when the ring is full.
+func (r *TraceRing) Add(t Trace) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.traces[r.next] = t
+	r.next = (r.next + 1) % len(r.traces)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// SetDetailed makes the ring ask for headers and bodies from now on.
+func (r *TraceRing) SetDetailed() {
+	r.detailed.Store(true)
+}
+
+// Detailed reports whether SetDetailed was called.
+func (r *TraceRing) Detailed() bool {
+	return r.detailed.Load()
+}
+
+// List returns the recorded traces, oldest first.
+func (r *TraceRing) List() []Trace {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]Trace(nil), r.traces[:r.next]...)
+	}
+	return append(append([]Trace(nil), r.traces[r.next:]...), r.traces[:r.next]...)
+}
+
+// TraceLogger returns a trace hook writing every trace to w as text.
+func TraceLogger(w io.Writer) func(Trace) {
+	var mu sync.Mutex
+	return func(t Trace) {
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s %s\n", t.Time.Format(time.RFC3339Nano), t.Summary())
+		fmt.Fprintf(&b, "  URL: %s\n", t.URL)
+		for _, name := range slices.Sorted(maps.Keys(t.Header)) {
+			fmt.Fprintf(&b, "  > %s: %s\n", name, strings.Join(t.Header[name], ", "))
+		}
+		if t.Request != "" {
+			fmt.Fprintf(&b, "  > %s\n", t.Request)
+		}
+		if t.Response != "" {
+			fmt.Fprintf(&b, "  < %s\n", t.Response)
+		}
+		if t.Err != "" {
+			fmt.Fprintf(&b, "  ! %s\n", t.Err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = io.WriteString(w, b.String()+"\n")
+	}
+}
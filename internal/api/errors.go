@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrUnauthorized is returned when the server returns HTTP 401 or API code 401,
+// indicating the session has expired and the user must log in again.
+var ErrUnauthorized = errors.New("session expired: please log in again")
+
+// Error classes of an APIError, for use with errors.Is.
+var (
+	ErrForbidden  = errors.New("permission denied")
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("invalid request")
+	ErrServer     = errors.New("server error")
+	// ErrUnsupported is the class of requests to an endpoint the server
+	// does not have, usually because it is older than cvx.
+	ErrUnsupported = errors.New("not supported by this server")
+)
+
+// APIError is a request the server answered with an error, either through
+// the HTTP status or the code of the ResultVO envelope.
+//
+//	var apiErr *api.APIError
+//	if errors.As(err, &apiErr) { ... apiErr.Code ... }
+//	if errors.Is(err, api.ErrNotFound) { ... }
+type APIError struct {
+	// Status is the HTTP status code of the response.
+	Status int
+	// Code, Msg and Timestamp come from the ResultVO envelope; they are zero
+	// when the body was not a ResultVO (e.g. an HTML page from a proxy).
+	Code      int
+	Msg       string
+	Timestamp string
+	// Method and Endpoint identify the request, e.g. "GET" and "/api/v1/user/profile".
+	Method   string
+	Endpoint string
+
+	// notJSON is set when a successful response was not JSON, e.g. the web
+	// UI's index page served for an unknown API path.
+	notJSON bool
+}
+
+// code is the most specific error code: the envelope's, else the HTTP status.
+func (e *APIError) code() int {
+	if e.Code != 0 && e.Code != http.StatusOK {
+		return e.Code
+	}
+	return e.Status
+}
+
+// Message returns the server's message, or the HTTP status text if there is none.
+func (e *APIError) Message() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	return http.StatusText(e.code())
+}
+
+func (e *APIError) Error() string {
+	if e.notJSON {
+		if c, ok := capabilityOf(e.Endpoint); ok {
+			return (&UnsupportedError{Capability: c}).Error()
+		}
+		return fmt.Sprintf("%s %s is not supported by this CertVault server", e.Method, e.Endpoint)
+	}
+	if e.code() == http.StatusUnauthorized {
+		return ErrUnauthorized.Error()
+	}
+	return fmt.Sprintf("API error %d: %s", e.code(), e.Message())
+}
+
+// Is matches the sentinel error of e's class.
+func (e *APIError) Is(target error) bool {
+	return target != nil && target == e.class()
+}
+
+func (e *APIError) class() error {
+	switch c := e.code(); {
+	case e.notJSON:
+		return ErrUnsupported
+	case c == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case c == http.StatusForbidden:
+		return ErrForbidden
+	case c == http.StatusNotFound:
+		return ErrNotFound
+	case c == http.StatusConflict:
+		return ErrConflict
+	case c == http.StatusBadRequest || c == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case c >= 500 && c < 600:
+		return ErrServer
+	}
+	return nil
+}
+
+// newAPIError describes a failed response; result may be nil when the body
+// could not be decoded.
+func newAPIError[T any](resp *http.Response, result *ResultVO[T]) *APIError {
+	e := &APIError{Status: resp.StatusCode}
+	if result != nil {
+		e.Code, e.Msg, e.Timestamp = result.Code, result.Msg, result.Timestamp
+	}
+	if req := resp.Request; req != nil {
+		e.Method, e.Endpoint = req.Method, req.URL.Path
+	}
+	return e
+}
+
+// Unreachable reports whether err means the server could not be reached:
+// the connection failed or timed out, or a gateway in front of the server
+// answered for it (HTTP 502, 503 or 504). A cancelled request is not, and
+// neither is a server whose certificate fails verification or pinning: that
+// may be someone impersonating it, which must not be hidden.
+func Unreachable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || untrustedServer(err) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Status {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// untrustedServer reports whether err is a failed check of the server's
+// certificate.
+func untrustedServer(err error) bool {
+	var (
+		verifyErr    *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+	return errors.Is(err, ErrPinMismatch) ||
+		errors.As(err, &verifyErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr)
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+)
+
+func TestAPIErrorClass(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *APIError
+		want error
+	}{
+		{name: "HTTP 401", err: &APIError{Status: http.StatusUnauthorized}, want: ErrUnauthorized},
+		{name: "envelope 401", err: &APIError{Status: http.StatusOK, Code: 401}, want: ErrUnauthorized},
+		{name: "envelope 403", err: &APIError{Status: http.StatusOK, Code: 403}, want: ErrForbidden},
+		{name: "HTTP 404", err: &APIError{Status: http.StatusNotFound}, want: ErrNotFound},
+		{name: "envelope 404", err: &APIError{Status: http.StatusOK, Code: 404}, want: ErrNotFound},
+		{name: "HTTP 405", err: &APIError{Status: http.StatusMethodNotAllowed}, want: nil},
+		{name: "envelope 409", err: &APIError{Status: http.StatusOK, Code: 409}, want: ErrConflict},
+		{name: "envelope 400", err: &APIError{Status: http.StatusOK, Code: 400}, want: ErrValidation},
+		{name: "HTTP 422", err: &APIError{Status: http.StatusUnprocessableEntity}, want: ErrValidation},
+		{name: "HTTP 502", err: &APIError{Status: http.StatusBadGateway}, want: ErrServer},
+		{name: "envelope 500", err: &APIError{Status: http.StatusOK, Code: 500}, want: ErrServer},
+		{name: "envelope code wins", err: &APIError{Status: http.StatusInternalServerError, Code: 404}, want: ErrNotFound},
+		{name: "not JSON", err: &APIError{Status: http.StatusOK, notJSON: true}, want: ErrUnsupported},
+		{name: "unknown code", err: &APIError{Status: http.StatusOK, Code: 418}, want: nil},
+	}
+	sentinels := []error{ErrUnauthorized, ErrForbidden, ErrNotFound, ErrConflict, ErrValidation, ErrServer, ErrUnsupported}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.class(); got != tt.want {
+				t.Fatalf("class() = %v, want %v", got, tt.want)
+			}
+			wrapped := fmt.Errorf("request: %w", tt.err)
+			for _, s := range sentinels {
+				if got := errors.Is(wrapped, s); got != (s == tt.want) {
+					t.Errorf("errors.Is(err, %q) = %v", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMissingEndpoint(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "HTTP 404", err: &APIError{Status: http.StatusNotFound}, want: true},
+		{name: "HTTP 405", err: &APIError{Status: http.StatusMethodNotAllowed}, want: true},
+		{name: "not JSON", err: &APIError{Status: http.StatusOK, notJSON: true}, want: true},
+		{name: "envelope 404", err: &APIError{Status: http.StatusOK, Code: 404}, want: false},
+		{name: "envelope 403", err: &APIError{Status: http.StatusOK, Code: 403}, want: false},
+		{name: "other error", err: errors.New("connection refused"), want: false},
+		{name: "nil", err: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := missingEndpoint(tt.err); got != tt.want {
+				t.Errorf("missingEndpoint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	urlErr := func(err error) error {
+		return fmt.Errorf("list certs: %w", &url.Error{Op: "Get", URL: "https://certvault.example", Err: err})
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "connection refused", err: urlErr(&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}), want: true},
+		{name: "HTTP 502", err: &APIError{Status: http.StatusBadGateway}, want: true},
+		{name: "HTTP 503", err: &APIError{Status: http.StatusServiceUnavailable}, want: true},
+		{name: "HTTP 504", err: &APIError{Status: http.StatusGatewayTimeout}, want: true},
+		{name: "HTTP 500", err: &APIError{Status: http.StatusInternalServerError}},
+		{name: "envelope 404", err: &APIError{Status: http.StatusOK, Code: 404}},
+		{name: "cancelled", err: urlErr(context.Canceled)},
+		{name: "unknown authority", err: urlErr(&tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}})},
+		{name: "hostname mismatch", err: urlErr(x509.HostnameError{Host: "certvault.example"})},
+		{name: "expired certificate", err: urlErr(x509.CertificateInvalidError{Reason: x509.Expired})},
+		{name: "pin mismatch", err: urlErr(fmt.Errorf("%w (server sent 00)", ErrPinMismatch))},
+		{name: "nil"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unreachable(tt.err); got != tt.want {
+				t.Errorf("Unreachable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
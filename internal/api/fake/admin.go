@@ -0,0 +1,377 @@
+package fake
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+// sortedUsers returns the users matching keep, ordered by username.
+func (s *Store) sortedUsers(keep func(*user) bool) []api.AdminUser {
+	var out []api.AdminUser
+	for _, u := range s.users {
+		if keep == nil || keep(u) {
+			out = append(out, u.AdminUser)
+		}
+	}
+	slices.SortFunc(out, func(a, b api.AdminUser) int { return strings.Compare(a.Username, b.Username) })
+	return out
+}
+
+// adminCAs returns the CAs u manages: its own, or every CA for a superadmin.
+func (s *Store) adminCAs(u *user) []*ca {
+	if u.Role >= RoleSuperadmin {
+		return slices.Clone(s.cas)
+	}
+	var out []*ca
+	for _, c := range s.cas {
+		if c.info.Owner == u.Username {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// adminCA returns a CA managed by u.
+func (s *Store) adminCA(u *user, uuid string) (*ca, error) {
+	c := s.findCA(uuid)
+	if c == nil || !slices.Contains(s.adminCAs(u), c) {
+		return nil, notFound("CA not found")
+	}
+	return c, nil
+}
+
+// addCA stores a CA owned by u. A certificate with a path length of zero
+// does not allow sub-CAs.
+func (s *Store) addCA(u *user, cert *x509.Certificate, key crypto.Signer, parent, comment string) *ca {
+	c := &ca{
+		info: api.CACert{
+			UUID:       newUUID(),
+			Owner:      u.Username,
+			AllowSubCa: !(cert.MaxPathLen == 0 && cert.MaxPathLenZero),
+			ParentCa:   parent,
+			Comment:    comment,
+			Available:  true,
+			NotBefore:  formatTime(cert.NotBefore),
+			NotAfter:   formatTime(cert.NotAfter),
+		},
+		cert:  cert,
+		key:   key,
+		bound: map[string]bool{},
+	}
+	s.cas = append(s.cas, c)
+	return c
+}
+
+// ListAdminUsers lists every user.
+func (f *Service) ListAdminUsers(ctx context.Context, page, size int) (*api.PageDTO[api.AdminUser], error) {
+	defer f.lock()()
+	if _, err := f.current(RoleAdmin); err != nil {
+		return nil, err
+	}
+	return paginate(f.store.sortedUsers(nil), page, size), nil
+}
+
+// CountAdminUsers counts every user.
+func (f *Service) CountAdminUsers(ctx context.Context) (int64, error) {
+	defer f.lock()()
+	if _, err := f.current(RoleAdmin); err != nil {
+		return 0, err
+	}
+	return int64(len(f.store.users)), nil
+}
+
+// ListAdminCAs lists the CAs the admin manages.
+func (f *Service) ListAdminCAs(ctx context.Context, page, size int) (*api.PageDTO[api.CACert], error) {
+	defer f.lock()()
+	u, err := f.current(RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(caInfos(f.store.adminCAs(u)), page, size), nil
+}
+
+// GetAdminCACert returns a managed CA's certificate as base64-encoded PEM.
+func (f *Service) GetAdminCACert(ctx context.Context, uuid string, chain, needRoot bool) (string, error) {
+	defer f.lock()()
+	u, err := f.current(RoleAdmin)
+	if err != nil {
+		return "", err
+	}
+	c, err := f.store.adminCA(u, uuid)
+	if err != nil {
+		return "", err
+	}
+	return f.store.chainPEM(c.cert, c.info.ParentCa, chain, needRoot), nil
+}
+
+// GetAdminCAPrivKey returns a CA key as base64-encoded PEM after checking
+// the admin's password.
+func (f *Service) GetAdminCAPrivKey(ctx context.Context, uuid, password string) (string, error) {
+	defer f.lock()()
+	u, err := f.current(RoleAdmin)
+	if err != nil {
+		return "", err
+	}
+	if password != u.password {
+		return "", validation("Wrong password")
+	}
+	c, err := f.store.adminCA(u, uuid)
+	if err != nil {
+		return "", err
+	}
+	data, err := encodeKey(c.key)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// UpdateAdminCAComment changes a CA's comment.
+func (f *Service) UpdateAdminCAComment(ctx context.Context, uuid, comment string) error {
+	defer f.lock()()
+	u, err := f.current(RoleAdmin)
+	if err != nil {
+		return err
+	}
+	c, err := f.store.adminCA(u, uuid)
+	if err != nil {
+		return err
+	}
+	c.info.Comment = comment
+	return nil
+}
+
+// ToggleAdminCAAvailable enables or disables issuing from a CA.
+func (f *Service) ToggleAdminCAAvailable(ctx context.Context, uuid string, available bool) error {
+	defer f.lock()()
+	u, err := f.current(RoleAdmin)
+	if err != nil {
+		return err
+	}
+	c, err := f.store.adminCA(u, uuid)
+	if err != nil {
+		return err
+	}
+	c.info.Available = available
+	return nil
+}
+
+// ImportAdminCA imports a CA certificate and its key. The issuer of an
+// intermediate CA must already be known.
+func (f *Service) ImportAdminCA(ctx context.Context, req api.ImportCACertRequest) (*api.CACert, error) {
+	defer f.lock()()
+	u, err := f.current(RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := parseCert(decodeInput(req.Certificate))
+	if err != nil {
+		return nil, err
+	}
+	key, err := parseKey(decodeInput(req.PrivKey))
+	if err != nil {
+		return nil, err
+	}
+	if pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(cert.PublicKey) {
+		return nil, validation("Private key does not match the certificate")
+	}
+	if !cert.IsCA {
+		return nil, validation("Certificate is not a CA certificate")
+	}
+	var parent string
+	if !bytes.Equal(cert.RawIssuer, cert.RawSubject) || cert.CheckSignatureFrom(cert) != nil {
+		for _, c := range f.store.cas {
+			if cert.CheckSignatureFrom(c.cert) == nil {
+				parent = c.info.UUID
+				break
+			}
+		}
+		if parent == "" {
+			return nil, validation("Issuer of the CA certificate is unknown; import it first")
+		}
+	}
+	c := f.store.addCA(u, cert, key, parent, req.Comment)
+	info := c.info
+	return &info, nil
+}
+
+// RequestAdminCA creates a root CA, or an intermediate CA under CaUUID.
+func (f *Service) RequestAdminCA(ctx context.Context, req api.RequestCACertRequest) (*api.CACert, error) {
+	defer f.lock()()
+	u, err := f.current(RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	var parent *ca
+	if req.CaUUID != "" {
+		if parent = f.store.findCA(req.CaUUID); parent == nil ||
+			!(slices.Contains(f.store.adminCAs(u), parent) || slices.Contains(f.store.userCAs(u), parent)) {
+			return nil, notFound("Parent CA not found")
+		}
+		if !parent.info.AllowSubCa {
+			return nil, validation("Parent CA does not allow sub-CAs")
+		}
+		if !parent.info.Available {
+			return nil, validation("Parent CA is not available")
+		}
+	}
+	if req.CommonName == "" {
+		return nil, validation("Common name is required")
+	}
+	tmpl, err := template(subject(req.Country, req.Province, req.City, req.Organization, req.OrganizationalUnit, req.CommonName), req.Expiry)
+	if err != nil {
+		return nil, err
+	}
+	caTemplate(tmpl, req.AllowSubCa)
+	key, err := generateKey(req.Algorithm, req.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		parentUUID string
+		parentCert *x509.Certificate
+		parentKey  crypto.Signer
+	)
+	if parent != nil {
+		parentUUID, parentCert, parentKey = parent.info.UUID, parent.cert, parent.key
+	}
+	cert, err := sign(tmpl, key, parentCert, parentKey)
+	if err != nil {
+		return nil, apiError(http.StatusInternalServerError, "sign certificate: %v", err)
+	}
+	c := f.store.addCA(u, cert, key, parentUUID, req.Comment)
+	info := c.info
+	return &info, nil
+}
+
+// RenewAdminCA reissues a CA certificate with the same key.
+func (f *Service) RenewAdminCA(ctx context.Context, uuid string, req api.RenewCACertRequest) (*api.CACert, error) {
+	defer f.lock()()
+	u, err := f.current(RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	c, err := f.store.adminCA(u, uuid)
+	if err != nil {
+		return nil, err
+	}
+	parentCert, parentKey := c.cert, c.key
+	if c.info.ParentCa != "" {
+		parent := f.store.findCA(c.info.ParentCa)
+		if parent == nil || !parent.info.Available {
+			return nil, validation("Parent CA is not available")
+		}
+		parentCert, parentKey = parent.cert, parent.key
+	}
+	cert, err := renew(c.cert, c.key, req.Expiry, parentCert, parentKey)
+	if err != nil {
+		return nil, err
+	}
+	c.cert = cert
+	c.info.NotBefore = formatTime(cert.NotBefore)
+	c.info.NotAfter = formatTime(cert.NotAfter)
+	info := c.info
+	return &info, nil
+}
+
+// DeleteAdminCA deletes a CA that has neither sub-CAs nor certificates.
+func (f *Service) DeleteAdminCA(ctx context.Context, uuid string) error {
+	defer f.lock()()
+	u, err := f.current(RoleAdmin)
+	if err != nil {
+		return err
+	}
+	c, err := f.store.adminCA(u, uuid)
+	if err != nil {
+		return err
+	}
+	for _, sub := range f.store.cas {
+		if sub.info.ParentCa == uuid {
+			return apiError(http.StatusConflict, "CA still has sub-CAs")
+		}
+	}
+	for _, cert := range f.store.certs {
+		if cert.info.CaUUID == uuid {
+			return apiError(http.StatusConflict, "CA still has issued certificates")
+		}
+	}
+	f.store.cas = slices.DeleteFunc(f.store.cas, func(x *ca) bool { return x == c })
+	return nil
+}
+
+// CountAdminCAs counts the CAs the admin manages.
+func (f *Service) CountAdminCAs(ctx context.Context) (int64, error) {
+	defer f.lock()()
+	u, err := f.current(RoleAdmin)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(f.store.adminCAs(u))), nil
+}
+
+// BindUsersToCA allows users to issue certificates from a CA.
+func (f *Service) BindUsersToCA(ctx context.Context, caUUID string, usernames []string) error {
+	return f.bind(caUUID, usernames, true)
+}
+
+// UnbindUsersFromCA revokes the users' access to a CA.
+func (f *Service) UnbindUsersFromCA(ctx context.Context, caUUID string, usernames []string) error {
+	return f.bind(caUUID, usernames, false)
+}
+
+func (f *Service) bind(caUUID string, usernames []string, bound bool) error {
+	defer f.lock()()
+	u, err := f.current(RoleAdmin)
+	if err != nil {
+		return err
+	}
+	c, err := f.store.adminCA(u, caUUID)
+	if err != nil {
+		return err
+	}
+	for _, name := range usernames {
+		if f.store.users[name] == nil {
+			return notFound("User %s not found", name)
+		}
+	}
+	for _, name := range usernames {
+		if bound {
+			c.bound[name] = true
+		} else {
+			delete(c.bound, name)
+		}
+	}
+	return nil
+}
+
+// GetBoundUsers lists the users bound to a CA.
+func (f *Service) GetBoundUsers(ctx context.Context, uuid string, page, size int) (*api.PageDTO[api.AdminUser], error) {
+	return f.bindings(uuid, page, size, true)
+}
+
+// GetUnboundUsers lists the users not bound to a CA.
+func (f *Service) GetUnboundUsers(ctx context.Context, uuid string, page, size int) (*api.PageDTO[api.AdminUser], error) {
+	return f.bindings(uuid, page, size, false)
+}
+
+func (f *Service) bindings(uuid string, page, size int, bound bool) (*api.PageDTO[api.AdminUser], error) {
+	defer f.lock()()
+	u, err := f.current(RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	c, err := f.store.adminCA(u, uuid)
+	if err != nil {
+		return nil, err
+	}
+	users := f.store.sortedUsers(func(x *user) bool { return c.bound[x.Username] == bound })
+	return paginate(users, page, size), nil
+}
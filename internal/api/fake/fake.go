@@ -0,0 +1,352 @@
+// Package fake is an in-memory implementation of the CertVault API.
+//
+// A Store holds users, CAs, certificates, bindings and sessions; every
+// Service obtained from it behaves like an api.Client logged in to the same
+// server, so several Services can act as different users:
+//
+//	store := fake.NewStore()
+//	admin := store.Client()
+//	_ = admin.Login(ctx, fake.DefaultUsername, fake.DefaultPassword)
+//
+// Roles, ownership and bindings are enforced like the server does, and
+// errors are *api.APIError values, so errors.Is(err, api.ErrForbidden) and
+// friends work. Certificates are real X.509 certificates.
+//
+// NewServer serves a Store over HTTP for code that uses api.Client.
+package fake
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+// Credentials of the superadmin every Store starts with.
+const (
+	DefaultUsername = "superadmin"
+	DefaultPassword = "superadmin"
+)
+
+// Roles as used by the API.
+const (
+	RoleUser       = 1
+	RoleAdmin      = 2
+	RoleSuperadmin = 3
+)
+
+// timeFormat is the date format of the API.
+const timeFormat = "2006-01-02T15:04:05"
+
+type user struct {
+	api.AdminUser
+	password string
+}
+
+type ca struct {
+	info  api.CACert
+	cert  *x509.Certificate
+	key   crypto.Signer
+	bound map[string]bool
+}
+
+type sslCert struct {
+	info api.SSLCert
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+type session struct {
+	record api.LoginRecord
+	token  string
+}
+
+// Store is the shared state of a fake server. It is safe for concurrent use.
+type Store struct {
+	mu       sync.Mutex
+	users    map[string]*user
+	cas      []*ca
+	certs    []*sslCert
+	sessions []*session
+	without  []api.Capability
+}
+
+// NewStore returns a Store containing only the DefaultUsername superadmin.
+func NewStore() *Store {
+	s := &Store{users: map[string]*user{}}
+	s.users[DefaultUsername] = &user{
+		AdminUser: api.AdminUser{Username: DefaultUsername, DisplayName: "Superadmin", Email: "superadmin@localhost", Role: RoleSuperadmin},
+		password:  DefaultPassword,
+	}
+	return s
+}
+
+// Without makes s act like an older server lacking caps: Probe reports them
+// missing and Handler answers their endpoints with HTTP 404. It replaces the
+// caps of earlier calls.
+func (s *Store) Without(caps ...api.Capability) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.without = slices.Clone(caps)
+}
+
+// lacks reports whether the endpoint at path belongs to a capability s was
+// told to lack.
+func (s *Store) lacks(path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.ContainsFunc(s.without, func(c api.Capability) bool { return c.Matches(path) })
+}
+
+// Client returns a new, logged-out Service on s.
+func (s *Store) Client() *Service {
+	return &Service{store: s, baseURL: "memory://certvault"}
+}
+
+// New returns a logged-out Service on a new Store.
+func New() *Service {
+	return NewStore().Client()
+}
+
+// Service implements api.Service on a Store. Its session is the equivalent
+// of the JSESSIONID cookie of api.Client.
+type Service struct {
+	store   *Store
+	session string
+	baseURL string
+	hooks   []func(string)
+
+	// notifyMu orders the calls of the session hooks.
+	notifyMu sync.Mutex
+}
+
+var _ api.Service = (*Service)(nil)
+
+// Store returns the state shared by f and the Services it was created with.
+func (f *Service) Store() *Store {
+	return f.store
+}
+
+// --- errors ---
+
+func apiError(code int, format string, args ...any) error {
+	return &api.APIError{Code: code, Msg: fmt.Sprintf(format, args...), Timestamp: formatTime(time.Now())}
+}
+
+func validation(format string, args ...any) error {
+	return apiError(http.StatusBadRequest, format, args...)
+}
+
+func notFound(format string, args ...any) error {
+	return apiError(http.StatusNotFound, format, args...)
+}
+
+func forbidden() error {
+	return apiError(http.StatusForbidden, "Insufficient permissions")
+}
+
+// --- helpers; callers hold store.mu ---
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// paginate returns page (from 1) of list.
+func paginate[T any](list []T, page, size int) *api.PageDTO[T] {
+	out := &api.PageDTO[T]{Total: int64(len(list)), List: []T{}}
+	if page < 1 || size < 1 {
+		return out
+	}
+	start := (page - 1) * size
+	if start >= len(list) {
+		return out
+	}
+	out.List = slices.Clone(list[start:min(start+size, len(list))])
+	return out
+}
+
+// current returns the logged-in user, requiring at least role.
+func (f *Service) current(role int) (*user, error) {
+	for _, sess := range f.store.sessions {
+		if sess.token == f.session && f.session != "" {
+			u := f.store.users[sess.record.Username]
+			if u == nil {
+				break
+			}
+			if u.Role < role {
+				return nil, forbidden()
+			}
+			return u, nil
+		}
+	}
+	return nil, apiError(http.StatusUnauthorized, "Not logged in")
+}
+
+func (f *Service) lock() func() {
+	f.store.mu.Lock()
+	return f.store.mu.Unlock
+}
+
+// --- api.Connection ---
+
+// GetSession returns the session token.
+func (f *Service) GetSession() string {
+	defer f.lock()()
+	return f.session
+}
+
+// SetSession resumes a session, e.g. one created by another Service.
+func (f *Service) SetSession(session string) {
+	f.track(func() error {
+		defer f.lock()()
+		f.session = session
+		return nil
+	})
+}
+
+// OnSessionChange registers fn to be called with the new session whenever
+// it changes, like api.Client does.
+func (f *Service) OnSessionChange(fn func(session string)) {
+	defer f.lock()()
+	f.hooks = append(f.hooks, fn)
+}
+
+// Probe reports which of caps, or of all capabilities if none are given,
+// the store lacks; see Store.Without. Unless Without was called the result is
+// the zero api.Capabilities, which means every capability is supported. The
+// answer needs no request, so ctx is not consulted.
+func (f *Service) Probe(ctx context.Context, caps ...api.Capability) api.Capabilities {
+	if len(caps) == 0 {
+		caps = api.AllCapabilities()
+	}
+	defer f.lock()()
+	var missing []api.Capability
+	for _, c := range caps {
+		if slices.Contains(f.store.without, c) {
+			missing = append(missing, c)
+		}
+	}
+	return api.NewCapabilities(missing...)
+}
+
+// track runs change and calls the session hooks if it changed the session.
+// Like api.Client, it reads the session for the hooks only once notifyMu is
+// held, so the last call always has the latest session.
+func (f *Service) track(change func() error) error {
+	before := f.GetSession()
+	err := change()
+	if f.GetSession() == before {
+		return err
+	}
+	f.notifyMu.Lock()
+	defer f.notifyMu.Unlock()
+	f.store.mu.Lock()
+	session, hooks := f.session, f.hooks
+	f.store.mu.Unlock()
+	for _, fn := range hooks {
+		fn(session)
+	}
+	return err
+}
+
+// GetBaseURL returns the base URL, which is informational only.
+func (f *Service) GetBaseURL() string {
+	defer f.lock()()
+	return f.baseURL
+}
+
+// SetBaseURL sets the base URL, which is informational only.
+func (f *Service) SetBaseURL(url string) {
+	defer f.lock()()
+	f.baseURL = url
+}
+
+// SetHeaders does nothing; there is no HTTP request to add headers to.
+func (f *Service) SetHeaders(http.Header) {}
+
+// InsecureTLS returns false.
+func (f *Service) InsecureTLS() bool { return false }
+
+// OnTrace does nothing; the fake makes no HTTP requests.
+func (f *Service) OnTrace(func(api.Trace)) {}
+
+// OnTraceSummary does nothing; the fake makes no HTTP requests.
+func (f *Service) OnTraceSummary(func(api.Trace), func() bool) {}
+
+// --- api.AuthService ---
+
+// Ping always succeeds.
+func (f *Service) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Login checks the credentials and starts a new session.
+func (f *Service) Login(ctx context.Context, username, password string) error {
+	return f.track(func() error {
+		return f.login(username, password, "127.0.0.1", "CertVaultCLIX", runtime.GOOS)
+	})
+}
+
+// login starts a session recorded as coming from ip with the given browser
+// and operating system.
+func (f *Service) login(username, password, ip, browser, os string) error {
+	defer f.lock()()
+	u := f.store.users[username]
+	if u == nil || u.password != password {
+		return validation("Wrong username or password")
+	}
+	f.session = newUUID()
+	f.store.sessions = append(f.store.sessions, &session{
+		token: f.session,
+		record: api.LoginRecord{
+			UUID:      newUUID(),
+			Username:  username,
+			IPAddress: ip,
+			Region:    "Local",
+			Browser:   browser,
+			OS:        os,
+			LoginTime: formatTime(time.Now()),
+			IsOnline:  true,
+		},
+	})
+	return nil
+}
+
+// Logout ends the current session.
+func (f *Service) Logout(ctx context.Context) error {
+	return f.track(func() error {
+		defer f.lock()()
+		if _, err := f.current(RoleUser); err != nil {
+			return err
+		}
+		f.store.endSessions(func(sess *session) bool { return sess.token == f.session })
+		f.session = ""
+		return nil
+	})
+}
+
+// GetOIDCAuthURL fails: the fake has no OIDC provider.
+func (f *Service) GetOIDCAuthURL(ctx context.Context) (string, error) {
+	return "", notFound("OIDC login is not enabled")
+}
+
+// endSessions removes the sessions matching match.
+func (s *Store) endSessions(match func(*session) bool) {
+	s.sessions = slices.DeleteFunc(s.sessions, match)
+}
@@ -0,0 +1,49 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+func TestProbe(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		without []api.Capability
+		caps    []api.Capability
+		want    []api.Capability
+	}{
+		{name: "complete server"},
+		{name: "all probed", without: []api.Capability{api.CapTools, api.CapSessions}, want: []api.Capability{api.CapSessions, api.CapTools}},
+		{name: "one probed", without: []api.Capability{api.CapTools, api.CapSessions}, caps: []api.Capability{api.CapTools}, want: []api.Capability{api.CapTools}},
+		{name: "other probed", without: []api.Capability{api.CapStats}, caps: []api.Capability{api.CapCABindings}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewStore()
+			store.Without(tt.without...)
+			srv := NewServer(store)
+			defer srv.Close()
+			client := api.NewClient(srv.URL)
+			if err := client.Login(ctx, DefaultUsername, DefaultPassword); err != nil {
+				t.Fatal(err)
+			}
+
+			for name, svc := range map[string]api.Connection{"in-process": store.Client(), "HTTP": client} {
+				got := svc.Probe(ctx, tt.caps...)
+				if !slices.Equal(got.Missing(), tt.want) {
+					t.Errorf("%s: Probe() missing %v, want %v", name, got.Missing(), tt.want)
+				}
+				for _, c := range tt.want {
+					if err := got.Check(c); !errors.Is(err, api.ErrUnsupported) {
+						t.Errorf("%s: Check(%s) = %v, want ErrUnsupported", name, c, err)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package fake
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+// generateKey creates a private key for an API algorithm name. Sizes follow
+// the server: RSA bits (default 2048) or the EC curve size (default 256).
+func generateKey(algorithm string, size int) (crypto.Signer, error) {
+	switch strings.ToUpper(algorithm) {
+	case "", "RSA":
+		if size == 0 {
+			size = 2048
+		}
+		if size < 2048 || size > 8192 {
+			return nil, validation("unsupported RSA key size %d", size)
+		}
+		return rsa.GenerateKey(rand.Reader, size)
+	case "EC", "ECDSA":
+		var curve elliptic.Curve
+		switch size {
+		case 0, 256:
+			curve = elliptic.P256()
+		case 384:
+			curve = elliptic.P384()
+		case 521:
+			curve = elliptic.P521()
+		default:
+			return nil, validation("unsupported EC key size %d", size)
+		}
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	case "ED25519":
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	}
+	return nil, validation("unsupported key algorithm %q", algorithm)
+}
+
+// subject builds a distinguished name, leaving out empty attributes.
+func subject(country, province, city, org, unit, commonName string) pkix.Name {
+	var n pkix.Name
+	add := func(dst *[]string, v string) {
+		if v != "" {
+			*dst = []string{v}
+		}
+	}
+	add(&n.Country, country)
+	add(&n.Province, province)
+	add(&n.Locality, city)
+	add(&n.Organization, org)
+	add(&n.OrganizationalUnit, unit)
+	n.CommonName = commonName
+	return n
+}
+
+// template returns a certificate template valid for expiry days from now.
+func template(name pkix.Name, expiry int) (*x509.Certificate, error) {
+	if expiry <= 0 {
+		return nil, validation("expiry must be a positive number of days")
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      name,
+		NotBefore:    now,
+		NotAfter:     now.AddDate(0, 0, expiry),
+	}, nil
+}
+
+// caTemplate makes tmpl a CA certificate; maxPathLen 0 forbids sub-CAs.
+func caTemplate(tmpl *x509.Certificate, allowSubCa bool) {
+	tmpl.IsCA = true
+	tmpl.BasicConstraintsValid = true
+	tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+	if !allowSubCa {
+		tmpl.MaxPathLenZero = true
+	}
+}
+
+// leafTemplate makes tmpl a TLS server and client certificate with SANs.
+func leafTemplate(tmpl *x509.Certificate, sans []api.SubjectAltName) error {
+	tmpl.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	for _, san := range sans {
+		switch san.Type {
+		case "DNS_NAME", "DNS":
+			tmpl.DNSNames = append(tmpl.DNSNames, san.Value)
+		case "IP_ADDRESS", "IP":
+			ip := net.ParseIP(san.Value)
+			if ip == nil {
+				return validation("invalid IP address %q", san.Value)
+			}
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		case "EMAIL":
+			tmpl.EmailAddresses = append(tmpl.EmailAddresses, san.Value)
+		case "URI":
+			u, err := url.Parse(san.Value)
+			if err != nil {
+				return validation("invalid URI %q", san.Value)
+			}
+			tmpl.URIs = append(tmpl.URIs, u)
+		default:
+			return validation("unsupported subject alternative name type %q", san.Type)
+		}
+	}
+	return nil
+}
+
+// sign issues tmpl for key, signed by parent (self-signed when parent is nil).
+func sign(tmpl *x509.Certificate, key crypto.Signer, parent *x509.Certificate, parentKey crypto.Signer) (*x509.Certificate, error) {
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, key.Public(), parentKey)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// renew signs a copy of cert for key, valid for expiry days from now.
+func renew(cert *x509.Certificate, key crypto.Signer, expiry int, parent *x509.Certificate, parentKey crypto.Signer) (*x509.Certificate, error) {
+	tmpl, err := template(cert.Subject, expiry)
+	if err != nil {
+		return nil, err
+	}
+	tmpl.IsCA, tmpl.BasicConstraintsValid = cert.IsCA, cert.BasicConstraintsValid
+	tmpl.MaxPathLen, tmpl.MaxPathLenZero = cert.MaxPathLen, cert.MaxPathLenZero
+	tmpl.KeyUsage, tmpl.ExtKeyUsage = cert.KeyUsage, cert.ExtKeyUsage
+	tmpl.DNSNames, tmpl.IPAddresses = cert.DNSNames, cert.IPAddresses
+	tmpl.EmailAddresses, tmpl.URIs = cert.EmailAddresses, cert.URIs
+	return sign(tmpl, key, parent, parentKey)
+}
+
+func encodeCert(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+func encodeKey(key crypto.Signer) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// decodeInput accepts PEM or DER data either as is or base64-encoded, the
+// way the API transfers it, and returns the raw bytes.
+func decodeInput(s string) []byte {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "-----BEGIN") {
+		return []byte(s)
+	}
+	if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+		return b
+	}
+	return []byte(s)
+}
+
+// decodeBase64 decodes a field of the conversion endpoints, which take
+// base64 like the rest of the API. Raw PEM or DER is rejected so callers
+// that send it fail here rather than against a real server.
+func decodeBase64(field, s string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, validation("%s is not base64-encoded: %v", field, err)
+	}
+	return data, nil
+}
+
+// decodePEM decodes a base64-encoded PEM field and parses it with parse.
+func decodePEM[T any](field, s string, parse func([]byte) (T, error)) (T, error) {
+	var zero T
+	data, err := decodeBase64(field, s)
+	if err != nil {
+		return zero, err
+	}
+	if block, _ := pem.Decode(data); block == nil {
+		return zero, validation("%s is not PEM", field)
+	}
+	return parse(data)
+}
+
+// parseCert parses the first certificate of PEM or DER data.
+func parseCert(data []byte) (*x509.Certificate, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+	cert, err := x509.ParseCertificate(data)
+	if err != nil {
+		return nil, validation("invalid certificate: %v", err)
+	}
+	return cert, nil
+}
+
+// parseKey parses a PKCS#8, PKCS#1 or SEC 1 private key in PEM or DER form.
+func parseKey(data []byte) (crypto.Signer, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(data); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(data); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(data); err == nil {
+		return key, nil
+	}
+	return nil, validation("invalid or encrypted private key")
+}
+
+// keyInfo returns the API algorithm name and size of a key.
+func keyInfo(pub crypto.PublicKey) (string, int) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return "RSA", k.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "EC", k.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "ED25519", 256
+	}
+	return "Unknown", 0
+}
+
+// analyze describes a certificate like the server's analysis endpoint.
+func analyze(cert *x509.Certificate) *api.CertAnalysis {
+	algorithm, size := keyInfo(cert.PublicKey)
+	sans := append([]string(nil), cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, u := range cert.URIs {
+		sans = append(sans, u.String())
+	}
+	ext := map[string]string{}
+	if cert.BasicConstraintsValid {
+		ext["basicConstraints"] = fmt.Sprintf("CA:%v", cert.IsCA)
+	}
+	if len(cert.SubjectKeyId) > 0 {
+		ext["subjectKeyIdentifier"] = hex.EncodeToString(cert.SubjectKeyId)
+	}
+	return &api.CertAnalysis{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		NotBefore:    formatTime(cert.NotBefore),
+		NotAfter:     formatTime(cert.NotAfter),
+		SerialNumber: cert.SerialNumber.Text(16),
+		Algorithm:    cert.SignatureAlgorithm.String(),
+		IsCA:         cert.IsCA,
+		Fingerprint:  api.Fingerprint(cert.Raw),
+		PublicKey:    map[string]interface{}{"algorithm": algorithm, "size": size},
+		Extensions:   ext,
+		SANs:         sans,
+	}
+}
@@ -0,0 +1,84 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+// Seed fills s with demo data: an admin and two users whose passwords equal
+// their usernames ("admin", "alice", "bob"), a root CA owned by the
+// superadmin, an issuing CA owned by admin and bound to both users, and a few
+// certificates, one of them about to expire.
+func (s *Store) Seed() error {
+	ctx := context.Background()
+	superadmin := s.Client()
+	if err := superadmin.Login(ctx, DefaultUsername, DefaultPassword); err != nil {
+		return err
+	}
+	defer superadmin.Logout(ctx)
+	err := superadmin.BatchCreateUsers(ctx, []api.CreateUserRequest{
+		{Username: "admin", DisplayName: "Demo Admin", Email: "admin@example.test", Password: "admin", Role: RoleAdmin},
+		{Username: "alice", DisplayName: "Alice", Email: "alice@example.test", Password: "alice", Role: RoleUser},
+		{Username: "bob", DisplayName: "Bob", Email: "bob@example.test", Password: "bob", Role: RoleUser},
+	})
+	if err != nil {
+		return err
+	}
+	root, err := superadmin.RequestAdminCA(ctx, api.RequestCACertRequest{
+		AllowSubCa: true, Algorithm: "EC", KeySize: 384,
+		Country: "US", Organization: "CertVault Demo", CommonName: "CertVault Demo Root CA",
+		Expiry: 3650, Comment: "Demo root",
+	})
+	if err != nil {
+		return err
+	}
+	if err := superadmin.BindUsersToCA(ctx, root.UUID, []string{"admin"}); err != nil {
+		return err
+	}
+
+	admin := s.Client()
+	if err := admin.Login(ctx, "admin", "admin"); err != nil {
+		return err
+	}
+	defer admin.Logout(ctx)
+	issuing, err := admin.RequestAdminCA(ctx, api.RequestCACertRequest{
+		CaUUID: root.UUID, Algorithm: "EC",
+		Country: "US", Organization: "CertVault Demo", CommonName: "CertVault Demo Issuing CA",
+		Expiry: 1825, Comment: "Demo issuing CA",
+	})
+	if err != nil {
+		return err
+	}
+	if err := admin.BindUsersToCA(ctx, issuing.UUID, []string{"alice", "bob"}); err != nil {
+		return err
+	}
+
+	certs := []struct {
+		user, name, algorithm string
+		expiry                int
+	}{
+		{"alice", "www.example.test", "EC", 90},
+		{"alice", "api.example.test", "RSA", 365},
+		{"alice", "legacy.example.test", "RSA", 7},
+		{"bob", "bob.example.test", "EC", 30},
+	}
+	for _, c := range certs {
+		svc := s.Client()
+		if err := svc.Login(ctx, c.user, c.user); err != nil {
+			return err
+		}
+		_, err := svc.RequestSSLCert(ctx, api.RequestSSLCertRequest{
+			CaUUID: issuing.UUID, Algorithm: c.algorithm,
+			Organization: "CertVault Demo", CommonName: c.name,
+			SubjectAltNames: []api.SubjectAltName{{Type: "DNS_NAME", Value: c.name}},
+			Expiry:          c.expiry, Comment: c.name,
+		})
+		_ = svc.Logout(ctx)
+		if err != nil {
+			return fmt.Errorf("issue %s: %w", c.name, err)
+		}
+	}
+	return nil
+}
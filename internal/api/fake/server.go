@@ -0,0 +1,342 @@
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+// sessionCookie is the cookie carrying the session token.
+const sessionCookie = "JSESSIONID"
+
+// NewServer starts an HTTP server speaking the CertVault API on s, for use
+// with api.NewClient(server.URL). The caller must Close it.
+func NewServer(s *Store) *httptest.Server {
+	return httptest.NewServer(s.Handler())
+}
+
+// handlerFunc serves one endpoint as f, the Service of the request's session.
+// The returned value becomes the data of the ResultVO envelope.
+type handlerFunc func(f *Service, r *http.Request) (any, error)
+
+// noContent is returned for an empty page, which CertVault reports with
+// code 204 in the envelope.
+type noContent struct{}
+
+// Handler returns an http.Handler serving the CertVault API on s. Like the
+// real server it answers every API call with HTTP 200 and a ResultVO
+// envelope whose code carries the outcome, including 401 for a missing or
+// expired session and 204 for an empty page. Endpoints of capabilities
+// given to Without get a plain HTTP 404, as from a server predating them.
+func (s *Store) Handler() http.Handler {
+	mux := http.NewServeMux()
+	h := func(pattern string, fn handlerFunc) {
+		mux.Handle(pattern, s.serve(fn))
+	}
+
+	// Auth
+	h("GET /api/v1/test/ping", func(f *Service, r *http.Request) (any, error) {
+		return "pong", f.Ping(r.Context())
+	})
+	h("POST /api/v1/auth/login", withBody(func(f *Service, r *http.Request, req api.LoginRequest) (any, error) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+		browser, os := describeAgent(r.UserAgent())
+		return nil, f.login(req.Username, req.Password, ip, browser, os)
+	}))
+	h("DELETE /api/v1/auth/logout", func(f *Service, r *http.Request) (any, error) {
+		return nil, f.Logout(r.Context())
+	})
+	h("GET /api/v1/auth/oidc/authorization", func(f *Service, r *http.Request) (any, error) {
+		return f.GetOIDCAuthURL(r.Context())
+	})
+
+	// User: profile and sessions
+	h("GET /api/v1/user/profile", func(f *Service, r *http.Request) (any, error) {
+		return f.GetProfile(r.Context())
+	})
+	h("PATCH /api/v1/user/profile", withBody(func(f *Service, r *http.Request, req api.UpdateProfileRequest) (any, error) {
+		return nil, f.UpdateProfile(r.Context(), req)
+	}))
+	h("GET /api/v1/user/session", paged(func(f *Service, r *http.Request, page, limit int) (*api.PageDTO[api.LoginRecord], error) {
+		return f.ListUserSessions(r.Context(), page, limit)
+	}))
+	h("DELETE /api/v1/user/session/{uuid}/logout", func(f *Service, r *http.Request) (any, error) {
+		return nil, f.LogoutSession(r.Context(), r.PathValue("uuid"))
+	})
+	h("DELETE /api/v1/user/logout", func(f *Service, r *http.Request) (any, error) {
+		return nil, f.LogoutAllSessions(r.Context())
+	})
+
+	// User: CAs and certificates
+	h("GET /api/v1/user/cert/ca", paged(func(f *Service, r *http.Request, page, limit int) (*api.PageDTO[api.CACert], error) {
+		return f.ListUserCAs(r.Context(), page, limit)
+	}))
+	h("GET /api/v1/user/cert/ca/count", func(f *Service, r *http.Request) (any, error) {
+		return f.CountUserCAs(r.Context())
+	})
+	h("GET /api/v1/user/cert/ca/{uuid}/cer", func(f *Service, r *http.Request) (any, error) {
+		return f.GetUserCACert(r.Context(), r.PathValue("uuid"), query(r, "isChain"), query(r, "needRootCa"))
+	})
+	h("GET /api/v1/user/cert/ssl", paged(func(f *Service, r *http.Request, page, limit int) (*api.PageDTO[api.SSLCert], error) {
+		return f.ListUserSSLCerts(r.Context(), page, limit)
+	}))
+	h("POST /api/v1/user/cert/ssl", withBody(func(f *Service, r *http.Request, req api.RequestSSLCertRequest) (any, error) {
+		return f.RequestSSLCert(r.Context(), req)
+	}))
+	h("GET /api/v1/user/cert/ssl/count", func(f *Service, r *http.Request) (any, error) {
+		return f.CountUserSSLCerts(r.Context())
+	})
+	h("GET /api/v1/user/cert/ssl/{uuid}/cer", func(f *Service, r *http.Request) (any, error) {
+		return f.GetUserSSLCert(r.Context(), r.PathValue("uuid"), query(r, "isChain"), query(r, "needRootCa"))
+	})
+	h("POST /api/v1/user/cert/ssl/{uuid}/privkey", withBody(func(f *Service, r *http.Request, req api.GetPrivKeyRequest) (any, error) {
+		return f.GetUserSSLPrivKey(r.Context(), r.PathValue("uuid"), req.Password)
+	}))
+	h("PUT /api/v1/user/cert/ssl/{uuid}", withBody(func(f *Service, r *http.Request, req api.RenewSSLCertRequest) (any, error) {
+		return f.RenewSSLCert(r.Context(), r.PathValue("uuid"), req)
+	}))
+	h("DELETE /api/v1/user/cert/ssl/{uuid}", func(f *Service, r *http.Request) (any, error) {
+		return nil, f.DeleteSSLCert(r.Context(), r.PathValue("uuid"))
+	})
+	h("PATCH /api/v1/user/cert/ssl/{uuid}/comment", withBody(func(f *Service, r *http.Request, req api.UpdateCommentRequest) (any, error) {
+		return nil, f.UpdateSSLCertComment(r.Context(), r.PathValue("uuid"), req.Comment)
+	}))
+
+	// User: tools
+	h("POST /api/v1/user/cert/analyze", withBody(func(f *Service, r *http.Request, req api.AnalyzeCertRequest) (any, error) {
+		return f.AnalyzeCert(r.Context(), req.Cert)
+	}))
+	h("POST /api/v1/user/cert/privkey/analyze", withBody(func(f *Service, r *http.Request, req api.AnalyzePrivKeyRequest) (any, error) {
+		return f.AnalyzePrivKey(r.Context(), req.PrivKey, req.Password)
+	}))
+	h("POST /api/v1/user/cert/convert/pem/to/pfx", withBody(func(f *Service, r *http.Request, req api.ConvertPEMtoPFXRequest) (any, error) {
+		return f.ConvertPEMtoPFX(r.Context(), req)
+	}))
+	h("POST /api/v1/user/cert/convert/pem/to/der", withBody(func(f *Service, r *http.Request, req api.ConvertRequest) (any, error) {
+		return f.ConvertPEMtoDER(r.Context(), req.Cert)
+	}))
+	h("POST /api/v1/user/cert/convert/der/to/pem", withBody(func(f *Service, r *http.Request, req api.ConvertRequest) (any, error) {
+		return f.ConvertDERtoPEM(r.Context(), req.Cert)
+	}))
+
+	// Admin
+	h("GET /api/v1/admin/users", paged(func(f *Service, r *http.Request, page, limit int) (*api.PageDTO[api.AdminUser], error) {
+		return f.ListAdminUsers(r.Context(), page, limit)
+	}))
+	h("GET /api/v1/admin/users/count", func(f *Service, r *http.Request) (any, error) {
+		return f.CountAdminUsers(r.Context())
+	})
+	h("GET /api/v1/admin/cert/ca", paged(func(f *Service, r *http.Request, page, limit int) (*api.PageDTO[api.CACert], error) {
+		return f.ListAdminCAs(r.Context(), page, limit)
+	}))
+	h("POST /api/v1/admin/cert/ca", withBody(func(f *Service, r *http.Request, req api.RequestCACertRequest) (any, error) {
+		return f.RequestAdminCA(r.Context(), req)
+	}))
+	h("GET /api/v1/admin/cert/ca/count", func(f *Service, r *http.Request) (any, error) {
+		return f.CountAdminCAs(r.Context())
+	})
+	h("POST /api/v1/admin/cert/ca/import", withBody(func(f *Service, r *http.Request, req api.ImportCACertRequest) (any, error) {
+		return f.ImportAdminCA(r.Context(), req)
+	}))
+	h("GET /api/v1/admin/cert/ca/{uuid}/cer", func(f *Service, r *http.Request) (any, error) {
+		return f.GetAdminCACert(r.Context(), r.PathValue("uuid"), query(r, "isChain"), query(r, "needRootCa"))
+	})
+	h("POST /api/v1/admin/cert/ca/{uuid}/privkey", withBody(func(f *Service, r *http.Request, req api.GetPrivKeyRequest) (any, error) {
+		return f.GetAdminCAPrivKey(r.Context(), r.PathValue("uuid"), req.Password)
+	}))
+	h("PATCH /api/v1/admin/cert/ca/{uuid}/comment", withBody(func(f *Service, r *http.Request, req api.UpdateCommentRequest) (any, error) {
+		return nil, f.UpdateAdminCAComment(r.Context(), r.PathValue("uuid"), req.Comment)
+	}))
+	h("PATCH /api/v1/admin/cert/ca/{uuid}/available", withBody(func(f *Service, r *http.Request, req api.ToggleAvailableRequest) (any, error) {
+		return nil, f.ToggleAdminCAAvailable(r.Context(), r.PathValue("uuid"), req.Available)
+	}))
+	h("PUT /api/v1/admin/cert/ca/{uuid}", withBody(func(f *Service, r *http.Request, req api.RenewCACertRequest) (any, error) {
+		return f.RenewAdminCA(r.Context(), r.PathValue("uuid"), req)
+	}))
+	h("DELETE /api/v1/admin/cert/ca/{uuid}", func(f *Service, r *http.Request) (any, error) {
+		return nil, f.DeleteAdminCA(r.Context(), r.PathValue("uuid"))
+	})
+	h("POST /api/v1/admin/cert/ca/bind/create", withBody(func(f *Service, r *http.Request, req api.CABindingDTO) (any, error) {
+		return nil, f.BindUsersToCA(r.Context(), req.CaUUID, []string{req.Username})
+	}))
+	h("POST /api/v1/admin/cert/ca/bind/delete", withBody(func(f *Service, r *http.Request, req api.CABindingDTO) (any, error) {
+		return nil, f.UnbindUsersFromCA(r.Context(), req.CaUUID, []string{req.Username})
+	}))
+	h("GET /api/v1/admin/cert/ca/{uuid}/bind", paged(func(f *Service, r *http.Request, page, limit int) (*api.PageDTO[api.AdminUser], error) {
+		return f.GetBoundUsers(r.Context(), r.PathValue("uuid"), page, limit)
+	}))
+	h("GET /api/v1/admin/cert/ca/{uuid}/bind/not", paged(func(f *Service, r *http.Request, page, limit int) (*api.PageDTO[api.AdminUser], error) {
+		return f.GetUnboundUsers(r.Context(), r.PathValue("uuid"), page, limit)
+	}))
+
+	// Superadmin
+	h("GET /api/v1/superadmin/user/session", paged(func(f *Service, r *http.Request, page, limit int) (*api.PageDTO[api.LoginRecord], error) {
+		return f.ListAllSessions(r.Context(), page, limit)
+	}))
+	h("GET /api/v1/superadmin/user/session/{username}", paged(func(f *Service, r *http.Request, page, limit int) (*api.PageDTO[api.LoginRecord], error) {
+		return f.ListUserSessionsBySuperadmin(r.Context(), r.PathValue("username"), page, limit)
+	}))
+	h("DELETE /api/v1/superadmin/user/{username}/logout", func(f *Service, r *http.Request) (any, error) {
+		return nil, f.ForceLogoutUser(r.Context(), r.PathValue("username"))
+	})
+	h("POST /api/v1/superadmin/user", withBody(func(f *Service, r *http.Request, req api.CreateUserRequest) (any, error) {
+		return f.CreateUser(r.Context(), req)
+	}))
+	h("POST /api/v1/superadmin/users/create", withBody(func(f *Service, r *http.Request, req []api.CreateUserRequest) (any, error) {
+		return nil, f.BatchCreateUsers(r.Context(), req)
+	}))
+	h("POST /api/v1/superadmin/users/delete", withBody(func(f *Service, r *http.Request, usernames []string) (any, error) {
+		return nil, f.BatchDeleteUsers(r.Context(), usernames)
+	}))
+	h("PATCH /api/v1/superadmin/user/role", withBody(func(f *Service, r *http.Request, req api.UpdateUserRoleRequest) (any, error) {
+		return nil, f.UpdateUserRole(r.Context(), req)
+	}))
+	h("PATCH /api/v1/superadmin/user/{username}", withBody(func(f *Service, r *http.Request, req api.UpdateSuperadminUserRequest) (any, error) {
+		return nil, f.UpdateSuperadminUser(r.Context(), r.PathValue("username"), req)
+	}))
+	h("DELETE /api/v1/superadmin/user/{username}", func(f *Service, r *http.Request) (any, error) {
+		return nil, f.DeleteSuperadminUser(r.Context(), r.PathValue("username"))
+	})
+	h("GET /api/v1/superadmin/cert/ssl/count", func(f *Service, r *http.Request) (any, error) {
+		return f.CountAllSSLCerts(r.Context())
+	})
+	h("GET /api/v1/superadmin/cert/ca/count", func(f *Service, r *http.Request) (any, error) {
+		return f.CountAllCAs(r.Context())
+	})
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeResult(w, http.StatusNotFound, api.ResultVO[any]{
+			Code:      http.StatusNotFound,
+			Msg:       "No endpoint " + r.Method + " " + r.URL.Path,
+			Timestamp: formatTime(time.Now()),
+		})
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.lacks(r.URL.Path) {
+			http.NotFound(w, r)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// serve runs fn with the Service of the request's JSESSIONID cookie and
+// writes the envelope, setting or clearing the cookie when the session
+// changed.
+func (s *Store) serve(fn handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f := s.Client()
+		if c, err := r.Cookie(sessionCookie); err == nil {
+			f.session = c.Value
+		}
+		before := f.session
+
+		data, err := fn(f, r)
+		result := api.ResultVO[any]{Code: http.StatusOK, Msg: "Success", Data: data, Timestamp: formatTime(time.Now())}
+		var apiErr *api.APIError
+		switch {
+		case errors.As(err, &apiErr):
+			result.Code, result.Msg, result.Data = apiErr.Code, apiErr.Msg, nil
+		case err != nil:
+			result.Code, result.Msg, result.Data = http.StatusInternalServerError, err.Error(), nil
+		case data == noContent{}:
+			result.Code, result.Msg, result.Data = http.StatusNoContent, "No content", nil
+		}
+
+		switch {
+		case f.session == before:
+		case f.session == "":
+			http.SetCookie(w, &http.Cookie{Name: sessionCookie, Path: "/", MaxAge: -1, HttpOnly: true})
+		default:
+			http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: f.session, Path: "/", HttpOnly: true})
+		}
+		writeResult(w, http.StatusOK, result)
+	})
+}
+
+func writeResult(w http.ResponseWriter, status int, result api.ResultVO[any]) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// withBody adapts fn to a handlerFunc receiving the decoded JSON body.
+func withBody[T any](fn func(f *Service, r *http.Request, body T) (any, error)) handlerFunc {
+	return func(f *Service, r *http.Request) (any, error) {
+		var body T
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return nil, validation("Invalid request body: %v", err)
+		}
+		return fn(f, r, body)
+	}
+}
+
+// paged adapts a list method to a handlerFunc, reading the page and limit
+// query parameters and reporting an empty page as noContent.
+func paged[T any](fn func(f *Service, r *http.Request, page, limit int) (*api.PageDTO[T], error)) handlerFunc {
+	return func(f *Service, r *http.Request) (any, error) {
+		page, err := intQuery(r, "page", 1)
+		if err != nil {
+			return nil, err
+		}
+		limit, err := intQuery(r, "limit", 10)
+		if err != nil {
+			return nil, err
+		}
+		result, err := fn(f, r, page, limit)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.List) == 0 {
+			return noContent{}, nil
+		}
+		return result, nil
+	}
+}
+
+func intQuery(r *http.Request, name string, def int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, validation("Invalid %s %q", name, v)
+	}
+	return n, nil
+}
+
+// query reports whether a boolean query parameter is true.
+func query(r *http.Request, name string) bool {
+	b, _ := strconv.ParseBool(r.URL.Query().Get(name))
+	return b
+}
+
+// describeAgent derives the browser and operating system shown in session
+// records from a User-Agent header.
+func describeAgent(ua string) (browser, os string) {
+	browser, os = "Unknown", "Unknown"
+	for _, b := range []string{"CertVaultCLIX", "curl", "Firefox", "Edg", "Chrome", "Safari"} {
+		if strings.Contains(ua, b) {
+			browser = strings.Replace(b, "Edg", "Edge", 1)
+			break
+		}
+	}
+	for _, o := range []string{"Windows", "Android", "iPhone", "Mac OS", "Linux"} {
+		if strings.Contains(ua, o) {
+			os = o
+			break
+		}
+	}
+	return browser, os
+}
@@ -0,0 +1,210 @@
+package fake
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+// ListAllSessions lists the sessions of every user.
+func (f *Service) ListAllSessions(ctx context.Context, page, limit int) (*api.PageDTO[api.LoginRecord], error) {
+	defer f.lock()()
+	if _, err := f.current(RoleSuperadmin); err != nil {
+		return nil, err
+	}
+	return paginate(f.store.records(""), page, limit), nil
+}
+
+// ListUserSessionsBySuperadmin lists the sessions of one user.
+func (f *Service) ListUserSessionsBySuperadmin(ctx context.Context, username string, page, limit int) (*api.PageDTO[api.LoginRecord], error) {
+	defer f.lock()()
+	if _, err := f.current(RoleSuperadmin); err != nil {
+		return nil, err
+	}
+	if f.store.users[username] == nil {
+		return nil, notFound("User %s not found", username)
+	}
+	return paginate(f.store.records(username), page, limit), nil
+}
+
+// ForceLogoutUser ends every session of a user.
+func (f *Service) ForceLogoutUser(ctx context.Context, username string) error {
+	defer f.lock()()
+	if _, err := f.current(RoleSuperadmin); err != nil {
+		return err
+	}
+	if f.store.users[username] == nil {
+		return notFound("User %s not found", username)
+	}
+	f.store.endSessions(func(sess *session) bool { return sess.record.Username == username })
+	return nil
+}
+
+// checkNewUser validates a user to be created.
+func (s *Store) checkNewUser(req api.CreateUserRequest) error {
+	if req.Username == "" || req.Password == "" {
+		return validation("Username and password are required")
+	}
+	if req.Role < RoleUser || req.Role > RoleSuperadmin {
+		return validation("Invalid role %d", req.Role)
+	}
+	if s.users[req.Username] != nil {
+		return apiError(http.StatusConflict, "User %s already exists", req.Username)
+	}
+	return nil
+}
+
+func (s *Store) addUser(req api.CreateUserRequest) *user {
+	u := &user{
+		AdminUser: api.AdminUser{Username: req.Username, DisplayName: req.DisplayName, Email: req.Email, Role: req.Role},
+		password:  req.Password,
+	}
+	s.users[u.Username] = u
+	return u
+}
+
+// deleteUser removes a user with its sessions and CA bindings. Its
+// certificates and CAs are kept.
+func (s *Store) deleteUser(username string) {
+	delete(s.users, username)
+	s.endSessions(func(sess *session) bool { return sess.record.Username == username })
+	for _, c := range s.cas {
+		delete(c.bound, username)
+	}
+}
+
+// CreateUser creates a user.
+func (f *Service) CreateUser(ctx context.Context, req api.CreateUserRequest) (*api.AdminUser, error) {
+	defer f.lock()()
+	if _, err := f.current(RoleSuperadmin); err != nil {
+		return nil, err
+	}
+	if err := f.store.checkNewUser(req); err != nil {
+		return nil, err
+	}
+	info := f.store.addUser(req).AdminUser
+	return &info, nil
+}
+
+// BatchCreateUsers creates all users or, if one is invalid, none.
+func (f *Service) BatchCreateUsers(ctx context.Context, users []api.CreateUserRequest) error {
+	defer f.lock()()
+	if _, err := f.current(RoleSuperadmin); err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, req := range users {
+		if err := f.store.checkNewUser(req); err != nil {
+			return err
+		}
+		if seen[req.Username] {
+			return apiError(http.StatusConflict, "User %s already exists", req.Username)
+		}
+		seen[req.Username] = true
+	}
+	for _, req := range users {
+		f.store.addUser(req)
+	}
+	return nil
+}
+
+// checkDelete validates that username exists and is not the current user.
+func (s *Store) checkDelete(current *user, username string) error {
+	if s.users[username] == nil {
+		return notFound("User %s not found", username)
+	}
+	if username == current.Username {
+		return validation("You cannot delete yourself")
+	}
+	return nil
+}
+
+// BatchDeleteUsers deletes all users or, if one cannot be deleted, none.
+func (f *Service) BatchDeleteUsers(ctx context.Context, usernames []string) error {
+	defer f.lock()()
+	u, err := f.current(RoleSuperadmin)
+	if err != nil {
+		return err
+	}
+	for _, name := range usernames {
+		if err := f.store.checkDelete(u, name); err != nil {
+			return err
+		}
+	}
+	for _, name := range usernames {
+		f.store.deleteUser(name)
+	}
+	return nil
+}
+
+// UpdateSuperadminUser changes the non-empty fields of a user.
+func (f *Service) UpdateSuperadminUser(ctx context.Context, username string, req api.UpdateSuperadminUserRequest) error {
+	defer f.lock()()
+	if _, err := f.current(RoleSuperadmin); err != nil {
+		return err
+	}
+	u := f.store.users[username]
+	if u == nil {
+		return notFound("User %s not found", username)
+	}
+	if req.DisplayName != "" {
+		u.DisplayName = req.DisplayName
+	}
+	if req.Email != "" {
+		u.Email = req.Email
+	}
+	if req.Password != "" {
+		u.password = req.Password
+	}
+	return nil
+}
+
+// UpdateUserRole changes the role of a user.
+func (f *Service) UpdateUserRole(ctx context.Context, req api.UpdateUserRoleRequest) error {
+	defer f.lock()()
+	if _, err := f.current(RoleSuperadmin); err != nil {
+		return err
+	}
+	u := f.store.users[req.Username]
+	if u == nil {
+		return notFound("User %s not found", req.Username)
+	}
+	if req.Role < RoleUser || req.Role > RoleSuperadmin {
+		return validation("Invalid role %d", req.Role)
+	}
+	u.Role = req.Role
+	return nil
+}
+
+// DeleteSuperadminUser deletes a user other than the current one.
+func (f *Service) DeleteSuperadminUser(ctx context.Context, username string) error {
+	defer f.lock()()
+	u, err := f.current(RoleSuperadmin)
+	if err != nil {
+		return err
+	}
+	if err := f.store.checkDelete(u, username); err != nil {
+		return err
+	}
+	f.store.deleteUser(username)
+	return nil
+}
+
+// CountAllSSLCerts counts every certificate.
+func (f *Service) CountAllSSLCerts(ctx context.Context) (int64, error) {
+	defer f.lock()()
+	if _, err := f.current(RoleSuperadmin); err != nil {
+		return 0, err
+	}
+	return int64(len(f.store.certs)), nil
+}
+
+// CountAllCAs counts every CA.
+func (f *Service) CountAllCAs(ctx context.Context) (int64, error) {
+	defer f.lock()()
+	if _, err := f.current(RoleSuperadmin); err != nil {
+		return 0, err
+	}
+	return int64(len(f.store.cas)), nil
+}
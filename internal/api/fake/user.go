@@ -0,0 +1,457 @@
+package fake
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+// --- profile and sessions ---
+
+// GetProfile returns the logged-in user's profile.
+func (f *Service) GetProfile(ctx context.Context) (*api.UserProfile, error) {
+	defer f.lock()()
+	u, err := f.current(RoleUser)
+	if err != nil {
+		return nil, err
+	}
+	return &api.UserProfile{Username: u.Username, DisplayName: u.DisplayName, Email: u.Email, Role: u.Role}, nil
+}
+
+// UpdateProfile changes the display name, email or password; a new
+// password needs the old one.
+func (f *Service) UpdateProfile(ctx context.Context, req api.UpdateProfileRequest) error {
+	defer f.lock()()
+	u, err := f.current(RoleUser)
+	if err != nil {
+		return err
+	}
+	if req.NewPassword != "" {
+		if req.OldPassword != u.password {
+			return validation("Old password is incorrect")
+		}
+		u.password = req.NewPassword
+	}
+	if req.DisplayName != "" {
+		u.DisplayName = req.DisplayName
+	}
+	if req.Email != "" {
+		u.Email = req.Email
+	}
+	return nil
+}
+
+// ListUserSessions lists the sessions of the logged-in user, newest first.
+func (f *Service) ListUserSessions(ctx context.Context, page, size int) (*api.PageDTO[api.LoginRecord], error) {
+	defer f.lock()()
+	u, err := f.current(RoleUser)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(f.store.records(u.Username), page, size), nil
+}
+
+// LogoutSession ends one of the logged-in user's sessions.
+func (f *Service) LogoutSession(ctx context.Context, uuid string) error {
+	defer f.lock()()
+	u, err := f.current(RoleUser)
+	if err != nil {
+		return err
+	}
+	n := len(f.store.sessions)
+	f.store.endSessions(func(sess *session) bool {
+		return sess.record.UUID == uuid && sess.record.Username == u.Username
+	})
+	if len(f.store.sessions) == n {
+		return notFound("Session not found")
+	}
+	return nil
+}
+
+// LogoutAllSessions ends every session of the logged-in user, including this one.
+func (f *Service) LogoutAllSessions(ctx context.Context) error {
+	return f.track(func() error {
+		defer f.lock()()
+		u, err := f.current(RoleUser)
+		if err != nil {
+			return err
+		}
+		f.store.endSessions(func(sess *session) bool { return sess.record.Username == u.Username })
+		f.session = ""
+		return nil
+	})
+}
+
+// records returns the session records of username, or of everyone if
+// username is empty, newest first.
+func (s *Store) records(username string) []api.LoginRecord {
+	var out []api.LoginRecord
+	for _, sess := range slices.Backward(s.sessions) {
+		if username == "" || sess.record.Username == username {
+			out = append(out, sess.record)
+		}
+	}
+	return out
+}
+
+// --- CAs ---
+
+// userCAs returns the CAs u may issue from: bound to u or owned by u.
+func (s *Store) userCAs(u *user) []*ca {
+	var out []*ca
+	for _, c := range s.cas {
+		if c.bound[u.Username] || c.info.Owner == u.Username {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func (s *Store) findCA(uuid string) *ca {
+	for _, c := range s.cas {
+		if c.info.UUID == uuid {
+			return c
+		}
+	}
+	return nil
+}
+
+// userCA returns a CA u has access to.
+func (s *Store) userCA(u *user, uuid string) (*ca, error) {
+	c := s.findCA(uuid)
+	if c == nil || !slices.Contains(s.userCAs(u), c) {
+		return nil, notFound("CA not found")
+	}
+	return c, nil
+}
+
+// chainPEM returns cert followed by its issuers, up to but excluding the
+// root unless needRoot is set, base64-encoded like the API.
+func (s *Store) chainPEM(cert *x509.Certificate, parent string, chain, needRoot bool) string {
+	data := encodeCert(cert)
+	for chain && parent != "" {
+		c := s.findCA(parent)
+		if c == nil || (c.info.ParentCa == "" && !needRoot) {
+			break
+		}
+		data = append(data, encodeCert(c.cert)...)
+		parent = c.info.ParentCa
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func caInfos(cas []*ca) []api.CACert {
+	out := make([]api.CACert, len(cas))
+	for i, c := range cas {
+		out[i] = c.info
+	}
+	return out
+}
+
+// ListUserCAs lists the CAs bound to the logged-in user.
+func (f *Service) ListUserCAs(ctx context.Context, page, size int) (*api.PageDTO[api.CACert], error) {
+	defer f.lock()()
+	u, err := f.current(RoleUser)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(caInfos(f.store.userCAs(u)), page, size), nil
+}
+
+// GetUserCACert returns a bound CA's certificate as base64-encoded PEM.
+func (f *Service) GetUserCACert(ctx context.Context, uuid string, chain, needRoot bool) (string, error) {
+	defer f.lock()()
+	u, err := f.current(RoleUser)
+	if err != nil {
+		return "", err
+	}
+	c, err := f.store.userCA(u, uuid)
+	if err != nil {
+		return "", err
+	}
+	return f.store.chainPEM(c.cert, c.info.ParentCa, chain, needRoot), nil
+}
+
+// CountUserCAs counts the CAs bound to the logged-in user.
+func (f *Service) CountUserCAs(ctx context.Context) (int64, error) {
+	defer f.lock()()
+	u, err := f.current(RoleUser)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(f.store.userCAs(u))), nil
+}
+
+// --- SSL certificates ---
+
+func (s *Store) userCerts(u *user) []*sslCert {
+	var out []*sslCert
+	for _, c := range s.certs {
+		if c.info.Owner == u.Username {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func (s *Store) userCert(u *user, uuid string) (*sslCert, error) {
+	for _, c := range s.certs {
+		if c.info.UUID == uuid && c.info.Owner == u.Username {
+			return c, nil
+		}
+	}
+	return nil, notFound("Certificate not found")
+}
+
+// ListUserSSLCerts lists the logged-in user's certificates.
+func (f *Service) ListUserSSLCerts(ctx context.Context, page, size int) (*api.PageDTO[api.SSLCert], error) {
+	defer f.lock()()
+	u, err := f.current(RoleUser)
+	if err != nil {
+		return nil, err
+	}
+	certs := f.store.userCerts(u)
+	infos := make([]api.SSLCert, len(certs))
+	for i, c := range certs {
+		infos[i] = c.info
+	}
+	return paginate(infos, page, size), nil
+}
+
+// GetUserSSLCert returns a certificate, optionally with its chain, as
+// base64-encoded PEM.
+func (f *Service) GetUserSSLCert(ctx context.Context, uuid string, chain, needRoot bool) (string, error) {
+	defer f.lock()()
+	u, err := f.current(RoleUser)
+	if err != nil {
+		return "", err
+	}
+	c, err := f.store.userCert(u, uuid)
+	if err != nil {
+		return "", err
+	}
+	return f.store.chainPEM(c.cert, c.info.CaUUID, chain, needRoot), nil
+}
+
+// GetUserSSLPrivKey returns a certificate's key as base64-encoded PEM after
+// checking the user's password.
+func (f *Service) GetUserSSLPrivKey(ctx context.Context, uuid, password string) (string, error) {
+	defer f.lock()()
+	u, err := f.current(RoleUser)
+	if err != nil {
+		return "", err
+	}
+	if password != u.password {
+		return "", validation("Wrong password")
+	}
+	c, err := f.store.userCert(u, uuid)
+	if err != nil {
+		return "", err
+	}
+	data, err := encodeKey(c.key)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// RequestSSLCert issues a certificate from a CA bound to the user.
+func (f *Service) RequestSSLCert(ctx context.Context, req api.RequestSSLCertRequest) (*api.SSLCert, error) {
+	defer f.lock()()
+	u, err := f.current(RoleUser)
+	if err != nil {
+		return nil, err
+	}
+	issuer, err := f.store.userCA(u, req.CaUUID)
+	if err != nil {
+		return nil, err
+	}
+	if !issuer.info.Available {
+		return nil, validation("CA is not available")
+	}
+	if req.CommonName == "" {
+		return nil, validation("Common name is required")
+	}
+	tmpl, err := template(subject(req.Country, req.Province, req.City, req.Organization, req.OrganizationalUnit, req.CommonName), req.Expiry)
+	if err != nil {
+		return nil, err
+	}
+	if err := leafTemplate(tmpl, req.SubjectAltNames); err != nil {
+		return nil, err
+	}
+	key, err := generateKey(req.Algorithm, req.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := sign(tmpl, key, issuer.cert, issuer.key)
+	if err != nil {
+		return nil, apiError(http.StatusInternalServerError, "sign certificate: %v", err)
+	}
+	now := formatTime(time.Now())
+	c := &sslCert{
+		info: api.SSLCert{
+			UUID:       newUUID(),
+			CaUUID:     issuer.info.UUID,
+			Owner:      u.Username,
+			Comment:    req.Comment,
+			NotBefore:  formatTime(cert.NotBefore),
+			NotAfter:   formatTime(cert.NotAfter),
+			CreatedAt:  now,
+			ModifiedAt: now,
+		},
+		cert: cert,
+		key:  key,
+	}
+	f.store.certs = append(f.store.certs, c)
+	info := c.info
+	return &info, nil
+}
+
+// RenewSSLCert reissues a certificate with the same key and names.
+func (f *Service) RenewSSLCert(ctx context.Context, uuid string, req api.RenewSSLCertRequest) (*api.SSLCert, error) {
+	defer f.lock()()
+	u, err := f.current(RoleUser)
+	if err != nil {
+		return nil, err
+	}
+	c, err := f.store.userCert(u, uuid)
+	if err != nil {
+		return nil, err
+	}
+	issuer := f.store.findCA(c.info.CaUUID)
+	if issuer == nil || !issuer.info.Available {
+		return nil, validation("CA is not available")
+	}
+	cert, err := renew(c.cert, c.key, req.Expiry, issuer.cert, issuer.key)
+	if err != nil {
+		return nil, err
+	}
+	c.cert = cert
+	c.info.NotBefore = formatTime(cert.NotBefore)
+	c.info.NotAfter = formatTime(cert.NotAfter)
+	c.info.ModifiedAt = formatTime(time.Now())
+	info := c.info
+	return &info, nil
+}
+
+// DeleteSSLCert deletes one of the user's certificates.
+func (f *Service) DeleteSSLCert(ctx context.Context, uuid string) error {
+	defer f.lock()()
+	u, err := f.current(RoleUser)
+	if err != nil {
+		return err
+	}
+	c, err := f.store.userCert(u, uuid)
+	if err != nil {
+		return err
+	}
+	f.store.certs = slices.DeleteFunc(f.store.certs, func(x *sslCert) bool { return x == c })
+	return nil
+}
+
+// UpdateSSLCertComment changes a certificate's comment.
+func (f *Service) UpdateSSLCertComment(ctx context.Context, uuid, comment string) error {
+	defer f.lock()()
+	u, err := f.current(RoleUser)
+	if err != nil {
+		return err
+	}
+	c, err := f.store.userCert(u, uuid)
+	if err != nil {
+		return err
+	}
+	c.info.Comment = comment
+	c.info.ModifiedAt = formatTime(time.Now())
+	return nil
+}
+
+// CountUserSSLCerts counts the user's certificates.
+func (f *Service) CountUserSSLCerts(ctx context.Context) (int64, error) {
+	defer f.lock()()
+	u, err := f.current(RoleUser)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(f.store.userCerts(u))), nil
+}
+
+// --- tools ---
+
+// AnalyzeCert describes a PEM or DER certificate, raw or base64-encoded.
+func (f *Service) AnalyzeCert(ctx context.Context, cert string) (*api.CertAnalysis, error) {
+	defer f.lock()()
+	if _, err := f.current(RoleUser); err != nil {
+		return nil, err
+	}
+	c, err := parseCert(decodeInput(cert))
+	if err != nil {
+		return nil, err
+	}
+	return analyze(c), nil
+}
+
+// AnalyzePrivKey describes an unencrypted private key.
+func (f *Service) AnalyzePrivKey(ctx context.Context, privKey, password string) (*api.PrivKeyAnalysis, error) {
+	defer f.lock()()
+	if _, err := f.current(RoleUser); err != nil {
+		return nil, err
+	}
+	key, err := parseKey(decodeInput(privKey))
+	if err != nil {
+		return nil, err
+	}
+	algorithm, size := keyInfo(key.Public())
+	return &api.PrivKeyAnalysis{Algorithm: algorithm, KeySize: size}, nil
+}
+
+// ConvertPEMtoPFX checks the base64-encoded PEM certificate and key, then
+// fails: the fake cannot encode PKCS#12.
+func (f *Service) ConvertPEMtoPFX(ctx context.Context, req api.ConvertPEMtoPFXRequest) (*api.ConvertResult, error) {
+	defer f.lock()()
+	if _, err := f.current(RoleUser); err != nil {
+		return nil, err
+	}
+	if _, err := decodePEM("cert", req.Cert, parseCert); err != nil {
+		return nil, err
+	}
+	if _, err := decodePEM("privkey", req.PrivKey, parseKey); err != nil {
+		return nil, err
+	}
+	return nil, apiError(http.StatusNotImplemented, "PFX conversion is not supported by the fake server")
+}
+
+// ConvertPEMtoDER returns the base64-encoded DER form of a base64-encoded
+// PEM certificate.
+func (f *Service) ConvertPEMtoDER(ctx context.Context, cert string) (*api.ConvertResult, error) {
+	defer f.lock()()
+	if _, err := f.current(RoleUser); err != nil {
+		return nil, err
+	}
+	c, err := decodePEM("cert", cert, parseCert)
+	if err != nil {
+		return nil, err
+	}
+	return &api.ConvertResult{Data: base64.StdEncoding.EncodeToString(c.Raw)}, nil
+}
+
+// ConvertDERtoPEM returns the PEM form of a base64-encoded DER certificate.
+func (f *Service) ConvertDERtoPEM(ctx context.Context, der string) (*api.ConvertResult, error) {
+	defer f.lock()()
+	if _, err := f.current(RoleUser); err != nil {
+		return nil, err
+	}
+	data, err := decodeBase64("cert", der)
+	if err != nil {
+		return nil, err
+	}
+	c, err := parseCert(data)
+	if err != nil {
+		return nil, err
+	}
+	return &api.ConvertResult{Data: string(encodeCert(c))}, nil
+}
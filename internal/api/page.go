@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"iter"
+)
+
+// DefaultPageSize is the page size used by Pages when none is given.
+const DefaultPageSize = 100
+
+// PageFunc fetches one page of a list endpoint. Pages are numbered from 1.
+// Every List* method of Client has this shape, e.g. client.ListUserCAs.
+type PageFunc[T any] func(ctx context.Context, page, size int) (*PageDTO[T], error)
+
+// PageOptions tunes a paginated listing.
+type PageOptions struct {
+	// Size is the number of items requested per page (DefaultPageSize if 0).
+	Size int
+	// MaxItems stops the listing after this many items (no limit if 0).
+	MaxItems int
+	// Prefetch requests the next page while the current one is consumed.
+	Prefetch bool
+}
+
+type pageResult[T any] struct {
+	page *PageDTO[T]
+	err  error
+}
+
+// Pages iterates over every item of a paginated endpoint, following Total
+// across pages. A failed request yields the error once and ends the
+// iteration; breaking out of the loop cancels any prefetched request.
+//
+//	for ca, err := range api.Pages(ctx, client.ListUserCAs, api.PageOptions{}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func Pages[T any](ctx context.Context, fetch PageFunc[T], opts PageOptions) iter.Seq2[T, error] {
+	size := opts.Size
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		get := func(n int) <-chan pageResult[T] {
+			ch := make(chan pageResult[T], 1)
+			go func() {
+				p, err := fetch(ctx, n, size)
+				ch <- pageResult[T]{p, err}
+			}()
+			return ch
+		}
+
+		var zero T
+		var next <-chan pageResult[T]
+		seen, emitted := 0, 0
+		for n := 1; ; n++ {
+			var res pageResult[T]
+			if next != nil {
+				res = <-next
+				next = nil
+			} else if err := ctx.Err(); err != nil {
+				res.err = err
+			} else {
+				res.page, res.err = fetch(ctx, n, size)
+			}
+			if res.err != nil {
+				yield(zero, res.err)
+				return
+			}
+
+			// A nil page, as fakes may return, counts as empty.
+			var list []T
+			var total int64
+			if res.page != nil {
+				list, total = res.page.List, res.page.Total
+			}
+			seen += len(list)
+			// A short page means the server has nothing more, even if Total
+			// disagrees (e.g. items deleted while paging).
+			more := len(list) >= size && int64(seen) < total &&
+				(opts.MaxItems <= 0 || seen < opts.MaxItems)
+			if more && opts.Prefetch {
+				next = get(n + 1)
+			}
+			for _, item := range list {
+				if opts.MaxItems > 0 && emitted >= opts.MaxItems {
+					return
+				}
+				if !yield(item, nil) {
+					return
+				}
+				emitted++
+			}
+			if !more {
+				return
+			}
+		}
+	}
+}
+
+// CollectPages returns every item of a paginated endpoint as a slice.
+func CollectPages[T any](ctx context.Context, fetch PageFunc[T], opts PageOptions) ([]T, error) {
+	var all []T
+	for item, err := range Pages(ctx, fetch, opts) {
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, item)
+	}
+	return all, nil
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pager serves items in pages and reports total as their Total.
+type pager struct {
+	items []int
+	total int64
+	// failAt makes the request of this page fail.
+	failAt int
+
+	mu     sync.Mutex
+	calls  []int
+	active sync.WaitGroup
+	// block holds the requests of pages above 1 until it is closed, or
+	// until their context is done.
+	block chan struct{}
+	// cancelled counts requests that ended because of their context.
+	cancelled int
+}
+
+var errPage = errors.New("page failed")
+
+func (p *pager) fetch(ctx context.Context, page, size int) (*PageDTO[int], error) {
+	p.active.Add(1)
+	defer p.active.Done()
+	p.mu.Lock()
+	p.calls = append(p.calls, page)
+	p.mu.Unlock()
+	if p.block != nil && page > 1 {
+		select {
+		case <-p.block:
+		case <-ctx.Done():
+			p.mu.Lock()
+			p.cancelled++
+			p.mu.Unlock()
+			return nil, ctx.Err()
+		}
+	}
+	if page == p.failAt {
+		return nil, errPage
+	}
+	from := min((page-1)*size, len(p.items))
+	to := min(from+size, len(p.items))
+	return &PageDTO[int]{Total: p.total, List: p.items[from:to]}, nil
+}
+
+// numbers returns 1 to n.
+func numbers(n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = i + 1
+	}
+	return out
+}
+
+func TestCollectPages(t *testing.T) {
+	tests := []struct {
+		name      string
+		items     int
+		total     int64
+		opts      PageOptions
+		want      []int
+		wantCalls []int
+	}{
+		{name: "empty", total: 0, opts: PageOptions{Size: 3}, want: nil, wantCalls: []int{1}},
+		{name: "exact pages", items: 6, total: 6, opts: PageOptions{Size: 3}, want: numbers(6), wantCalls: []int{1, 2}},
+		{name: "short last page", items: 7, total: 7, opts: PageOptions{Size: 3}, want: numbers(7), wantCalls: []int{1, 2, 3}},
+		{name: "total too high", items: 4, total: 10, opts: PageOptions{Size: 3}, want: numbers(4), wantCalls: []int{1, 2}},
+		{name: "total too low", items: 9, total: 3, opts: PageOptions{Size: 3}, want: numbers(3), wantCalls: []int{1}},
+		{name: "max items mid-page", items: 9, total: 9, opts: PageOptions{Size: 3, MaxItems: 4}, want: numbers(4), wantCalls: []int{1, 2}},
+		{name: "max items at page end", items: 9, total: 9, opts: PageOptions{Size: 3, MaxItems: 3}, want: numbers(3), wantCalls: []int{1}},
+		{name: "prefetch", items: 7, total: 7, opts: PageOptions{Size: 3, Prefetch: true}, want: numbers(7), wantCalls: []int{1, 2, 3}},
+		{name: "default size", items: 150, total: 150, want: numbers(150), wantCalls: []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &pager{items: numbers(tt.items), total: tt.total}
+			got, err := CollectPages(context.Background(), p.fetch, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("items = %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(p.calls, tt.wantCalls) {
+				t.Errorf("pages fetched = %v, want %v", p.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestPagesNilPage(t *testing.T) {
+	fetch := func(ctx context.Context, page, size int) (*PageDTO[int], error) { return nil, nil }
+	got, err := CollectPages(context.Background(), fetch, PageOptions{})
+	if err != nil || len(got) != 0 {
+		t.Errorf("CollectPages() = %v, %v; want no items", got, err)
+	}
+}
+
+func TestPagesError(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		p := &pager{items: numbers(9), total: 9, failAt: 2}
+		var got []int
+		var errs []error
+		for item, err := range Pages(context.Background(), p.fetch, PageOptions{Size: 3, Prefetch: prefetch}) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			got = append(got, item)
+		}
+		if !slices.Equal(got, numbers(3)) || len(errs) != 1 || !errors.Is(errs[0], errPage) {
+			t.Errorf("prefetch %v: items %v, errors %v; want 1-3 and errPage once", prefetch, got, errs)
+		}
+		if _, err := CollectPages(context.Background(), p.fetch, PageOptions{Size: 3, Prefetch: prefetch}); !errors.Is(err, errPage) {
+			t.Errorf("prefetch %v: CollectPages() error = %v, want errPage", prefetch, err)
+		}
+	}
+}
+
+func TestPagesPrefetchBreak(t *testing.T) {
+	p := &pager{items: numbers(9), total: 9, block: make(chan struct{})}
+	for range Pages(context.Background(), p.fetch, PageOptions{Size: 3, Prefetch: true}) {
+		break
+	}
+	// The prefetch of page 2 may start after the loop is left, but must
+	// then see its context cancelled and end.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		p.mu.Lock()
+		done := p.cancelled == 1
+		p.mu.Unlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("prefetched request not cancelled after break")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	p.active.Wait()
+	if !slices.Equal(p.calls, []int{1, 2}) {
+		t.Errorf("pages fetched = %v, want [1 2]", p.calls)
+	}
+}
+
+func TestPagesCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := &pager{items: numbers(9), total: 9}
+	var got []int
+	var gotErr error
+	for item, err := range Pages(ctx, p.fetch, PageOptions{Size: 3}) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		got = append(got, item)
+		if item == 3 {
+			cancel()
+		}
+	}
+	if !slices.Equal(got, numbers(3)) || !errors.Is(gotErr, context.Canceled) {
+		t.Errorf("items %v, error %v; want 1-3 and context.Canceled", got, gotErr)
+	}
+	if !slices.Equal(p.calls, []int{1}) {
+		t.Errorf("pages fetched = %v, want [1]", p.calls)
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how idempotent requests are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt (0 disables retries).
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry; it doubles on every retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff and the Retry-After delay a server may ask for.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is used by NewClient unless WithRetry is given.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   10 * time.Second,
+}
+
+// idempotent reports whether a request may be sent again without side effects.
+// PUT and DELETE are idempotent in HTTP terms but mutate server state, so
+// they are never retried implicitly.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// retryableStatus reports whether a response status is worth retrying:
+// gateway failures from a load balancer and rate limiting.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusTooManyRequests:
+		return true
+	}
+	return false
+}
+
+// retryableError reports whether a failed attempt may succeed when repeated:
+// it timed out, or the connection was refused, reset or closed, e.g. while
+// the server restarts. Other errors, such as a certificate that fails
+// verification or a pin mismatch, recur on every attempt.
+func retryableError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		switch opErr.Op {
+		case "dial", "read", "write":
+			// A name that does not resolve will not resolve on the next try.
+			var dnsErr *net.DNSError
+			if errors.As(err, &dnsErr) {
+				return dnsErr.IsTemporary
+			}
+			return true
+		}
+		return false
+	}
+	// The server closed a kept-alive connection before answering.
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// backoff returns the delay before retry number attempt (starting at 0):
+// exponential with "equal jitter", i.e. between half and all of the step.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay << attempt
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// nextDelay decides whether a failed attempt is retried and after how long.
+// resp and err are the outcome of the attempt; exactly one is non-nil.
+func (p RetryPolicy) nextDelay(ctx context.Context, attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if attempt >= p.MaxRetries || ctx.Err() != nil {
+		return 0, false
+	}
+	if err != nil {
+		// A cancelled or expired ctx was already ruled out above.
+		return p.backoff(attempt), retryableError(err)
+	}
+	if !retryableStatus(resp.StatusCode) {
+		return 0, false
+	}
+	if d, ok := retryAfter(resp); ok {
+		// Waiting longer than MaxDelay would look like a hang; report the error instead.
+		return d, d <= p.MaxDelay
+	}
+	return p.backoff(attempt), true
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// discard drains and closes a response body so the connection can be reused.
+func discard(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNextDelay(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second}
+	urlErr := func(err error) error {
+		return &url.Error{Op: "Get", URL: "https://certvault.example", Err: err}
+	}
+	status := func(code int, retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: code, Header: http.Header{}}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+
+	tests := []struct {
+		name    string
+		attempt int
+		resp    *http.Response
+		err     error
+		want    bool
+	}{
+		{name: "connection refused", err: urlErr(&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}), want: true},
+		{name: "connection reset", err: urlErr(&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}), want: true},
+		{name: "timeout", err: urlErr(&net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}), want: true},
+		{name: "connection closed", err: urlErr(io.EOF), want: true},
+		{name: "unknown host", err: urlErr(&net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", IsNotFound: true}})},
+		{name: "unknown authority", err: urlErr(&tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}})},
+		{name: "hostname mismatch", err: urlErr(x509.HostnameError{Host: "certvault.example"})},
+		{name: "pin mismatch", err: urlErr(fmt.Errorf("%w (server sent 00)", ErrPinMismatch))},
+		{name: "502", resp: status(http.StatusBadGateway, ""), want: true},
+		{name: "503", resp: status(http.StatusServiceUnavailable, ""), want: true},
+		{name: "504", resp: status(http.StatusGatewayTimeout, ""), want: true},
+		{name: "429 with short Retry-After", resp: status(http.StatusTooManyRequests, "1"), want: true},
+		{name: "429 with long Retry-After", resp: status(http.StatusTooManyRequests, "60")},
+		{name: "500", resp: status(http.StatusInternalServerError, "")},
+		{name: "200", resp: status(http.StatusOK, "")},
+		{name: "retries used up", attempt: 3, resp: status(http.StatusBadGateway, "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := p.nextDelay(context.Background(), tt.attempt, tt.resp, tt.err); got != tt.want {
+				t.Errorf("nextDelay() retry = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
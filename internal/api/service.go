@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"net/http"
+)
+
+// The interfaces below group the CertVault API by the role allowed to call
+// it. Client implements all of them; package fake provides an in-memory
+// implementation for tests and tooling that must run without a server.
+
+// Connection manages the client side of a connection to a server.
+type Connection interface {
+	GetSession() string
+	SetSession(session string)
+	GetBaseURL() string
+	SetBaseURL(url string)
+	SetHeaders(h http.Header)
+	InsecureTLS() bool
+	OnTrace(fn func(Trace))
+	OnTraceSummary(fn func(Trace), detail func() bool)
+	OnSessionChange(fn func(session string))
+	Probe(ctx context.Context, caps ...Capability) Capabilities
+}
+
+// AuthService covers the endpoints that need no session.
+type AuthService interface {
+	Ping(ctx context.Context) error
+	Login(ctx context.Context, username, password string) error
+	Logout(ctx context.Context) error
+	GetOIDCAuthURL(ctx context.Context) (string, error)
+}
+
+// UserService covers the endpoints available to every logged-in user.
+type UserService interface {
+	GetProfile(ctx context.Context) (*UserProfile, error)
+	UpdateProfile(ctx context.Context, req UpdateProfileRequest) error
+	ListUserSessions(ctx context.Context, page, size int) (*PageDTO[LoginRecord], error)
+	LogoutSession(ctx context.Context, uuid string) error
+	LogoutAllSessions(ctx context.Context) error
+
+	ListUserCAs(ctx context.Context, page, size int) (*PageDTO[CACert], error)
+	GetUserCACert(ctx context.Context, uuid string, chain, needRoot bool) (string, error)
+	CountUserCAs(ctx context.Context) (int64, error)
+
+	ListUserSSLCerts(ctx context.Context, page, size int) (*PageDTO[SSLCert], error)
+	GetUserSSLCert(ctx context.Context, uuid string, chain, needRoot bool) (string, error)
+	GetUserSSLPrivKey(ctx context.Context, uuid, password string) (string, error)
+	RequestSSLCert(ctx context.Context, req RequestSSLCertRequest) (*SSLCert, error)
+	RenewSSLCert(ctx context.Context, uuid string, req RenewSSLCertRequest) (*SSLCert, error)
+	DeleteSSLCert(ctx context.Context, uuid string) error
+	UpdateSSLCertComment(ctx context.Context, uuid, comment string) error
+	CountUserSSLCerts(ctx context.Context) (int64, error)
+
+	AnalyzeCert(ctx context.Context, cert string) (*CertAnalysis, error)
+	AnalyzePrivKey(ctx context.Context, privKey, password string) (*PrivKeyAnalysis, error)
+	ConvertPEMtoPFX(ctx context.Context, req ConvertPEMtoPFXRequest) (*ConvertResult, error)
+	ConvertPEMtoDER(ctx context.Context, cert string) (*ConvertResult, error)
+	ConvertDERtoPEM(ctx context.Context, der string) (*ConvertResult, error)
+}
+
+// AdminService covers the endpoints that need the Admin role.
+type AdminService interface {
+	ListAdminUsers(ctx context.Context, page, size int) (*PageDTO[AdminUser], error)
+	CountAdminUsers(ctx context.Context) (int64, error)
+
+	ListAdminCAs(ctx context.Context, page, size int) (*PageDTO[CACert], error)
+	GetAdminCACert(ctx context.Context, uuid string, chain, needRoot bool) (string, error)
+	GetAdminCAPrivKey(ctx context.Context, uuid, password string) (string, error)
+	UpdateAdminCAComment(ctx context.Context, uuid, comment string) error
+	ToggleAdminCAAvailable(ctx context.Context, uuid string, available bool) error
+	ImportAdminCA(ctx context.Context, req ImportCACertRequest) (*CACert, error)
+	RequestAdminCA(ctx context.Context, req RequestCACertRequest) (*CACert, error)
+	RenewAdminCA(ctx context.Context, uuid string, req RenewCACertRequest) (*CACert, error)
+	DeleteAdminCA(ctx context.Context, uuid string) error
+	CountAdminCAs(ctx context.Context) (int64, error)
+
+	BindUsersToCA(ctx context.Context, caUUID string, usernames []string) error
+	UnbindUsersFromCA(ctx context.Context, caUUID string, usernames []string) error
+	GetBoundUsers(ctx context.Context, uuid string, page, size int) (*PageDTO[AdminUser], error)
+	GetUnboundUsers(ctx context.Context, uuid string, page, size int) (*PageDTO[AdminUser], error)
+}
+
+// SuperadminService covers the endpoints that need the Superadmin role.
+type SuperadminService interface {
+	ListAllSessions(ctx context.Context, page, limit int) (*PageDTO[LoginRecord], error)
+	ListUserSessionsBySuperadmin(ctx context.Context, username string, page, limit int) (*PageDTO[LoginRecord], error)
+	ForceLogoutUser(ctx context.Context, username string) error
+
+	CreateUser(ctx context.Context, req CreateUserRequest) (*AdminUser, error)
+	BatchCreateUsers(ctx context.Context, users []CreateUserRequest) error
+	BatchDeleteUsers(ctx context.Context, usernames []string) error
+	UpdateSuperadminUser(ctx context.Context, username string, req UpdateSuperadminUserRequest) error
+	UpdateUserRole(ctx context.Context, req UpdateUserRoleRequest) error
+	DeleteSuperadminUser(ctx context.Context, username string) error
+
+	CountAllSSLCerts(ctx context.Context) (int64, error)
+	CountAllCAs(ctx context.Context) (int64, error)
+}
+
+// Service is the whole API as seen by one logged-in session.
+type Service interface {
+	Connection
+	AuthService
+	UserService
+	AdminService
+	SuperadminService
+}
+
+var _ Service = (*Client)(nil)
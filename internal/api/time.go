@@ -0,0 +1,29 @@
+package api
+
+import "time"
+
+// dateFormats lists date format strings accepted by the API, in priority order.
+var dateFormats = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05.999",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// ParseTime parses a date string in any of the formats used by the CertVault API.
+func ParseTime(s string) (time.Time, bool) {
+	for _, f := range dateFormats {
+		if t, err := time.Parse(f, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// DaysLeft returns the number of days until notAfter, or 0 if it cannot be parsed.
+func DaysLeft(notAfter string) int {
+	if t, ok := ParseTime(notAfter); ok {
+		return int(time.Until(t).Hours() / 24)
+	}
+	return 0
+}
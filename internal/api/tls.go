@@ -0,0 +1,144 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TLSOptions describes how the client trusts the server and authenticates to it.
+type TLSOptions struct {
+	// CAFile is a PEM bundle trusted in addition to the system roots.
+	CAFile string
+	// CertFile and KeyFile are a PEM client certificate and key for mutual TLS.
+	CertFile string
+	KeyFile  string
+	// PinSHA256 lists SHA-256 fingerprints (hex, colons optional, or base64)
+	// of which at least one must match the server certificate or a CA in its
+	// verified chain.
+	PinSHA256 []string
+	// Insecure disables certificate verification. Pins are still enforced, but
+	// only against the server certificate, as no chain is verified.
+	Insecure bool
+}
+
+// IsZero reports whether no TLS option is set.
+func (o TLSOptions) IsZero() bool {
+	return o.CAFile == "" && o.CertFile == "" && o.KeyFile == "" && len(o.PinSHA256) == 0 && !o.Insecure
+}
+
+// Config builds the tls.Config described by o.
+func (o TLSOptions) Config() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if o.CAFile != "" {
+		pemData, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", o.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if o.CertFile != "" || o.KeyFile != "" {
+		if o.CertFile == "" || o.KeyFile == "" {
+			return nil, errors.New("mutual TLS needs both a client certificate and a key")
+		}
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(o.PinSHA256) > 0 {
+		pins := make([][]byte, len(o.PinSHA256))
+		for i, p := range o.PinSHA256 {
+			pin, err := ParsePin(p)
+			if err != nil {
+				return nil, err
+			}
+			pins[i] = pin
+		}
+		// VerifyConnection runs even with InsecureSkipVerify, so pins hold in
+		// insecure mode too.
+		insecure := o.Insecure
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			return checkPins(pinCandidates(cs, insecure), pins)
+		}
+	}
+
+	cfg.InsecureSkipVerify = o.Insecure
+	return cfg, nil
+}
+
+// ParsePin decodes a SHA-256 fingerprint given as hex (e.g. the output of
+// `openssl x509 -fingerprint -sha256`, colons optional) or base64.
+func ParsePin(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "sha256/"), "SHA256:")
+	if b, err := hex.DecodeString(strings.ReplaceAll(s, ":", "")); err == nil && len(b) == sha256.Size {
+		return b, nil
+	}
+	if b, err := base64.StdEncoding.DecodeString(s); err == nil && len(b) == sha256.Size {
+		return b, nil
+	}
+	return nil, fmt.Errorf("invalid SHA-256 pin %q: want 64 hex digits or base64", s)
+}
+
+// Fingerprint returns the colon-separated SHA-256 fingerprint of a DER certificate.
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// pinCandidates returns the certificates of cs a pin may match, the server's
+// first. The chain the server sends is not to be trusted, so only verified
+// chains count; without verification only the server certificate does.
+func pinCandidates(cs tls.ConnectionState, insecure bool) []*x509.Certificate {
+	if insecure {
+		return cs.PeerCertificates[:min(1, len(cs.PeerCertificates))]
+	}
+	var certs []*x509.Certificate
+	for _, chain := range cs.VerifiedChains {
+		certs = append(certs, chain...)
+	}
+	return certs
+}
+
+// ErrPinMismatch is returned when the server certificate matches no pin.
+var ErrPinMismatch = errors.New("server certificate does not match any pinned SHA-256 fingerprint")
+
+// checkPins succeeds if any of certs matches a pin. The first certificate is
+// the server's.
+func checkPins(certs []*x509.Certificate, pins [][]byte) error {
+	for _, cert := range certs {
+		sum := sha256.Sum256(cert.Raw)
+		for _, pin := range pins {
+			if bytes.Equal(sum[:], pin) {
+				return nil
+			}
+		}
+	}
+	if len(certs) == 0 {
+		return errors.New("server presented no certificate to check against the pinned fingerprints")
+	}
+	return fmt.Errorf("%w (server sent %s)", ErrPinMismatch, Fingerprint(certs[0].Raw))
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// newTestCert returns a throwaway self-signed certificate.
+func newTestCert(t *testing.T, name string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func pinOf(cert *x509.Certificate) []byte {
+	sum := sha256.Sum256(cert.Raw)
+	return sum[:]
+}
+
+func TestCheckPins(t *testing.T) {
+	leaf := newTestCert(t, "server")
+	ca := newTestCert(t, "ca")
+	pinned := newTestCert(t, "pinned")
+
+	tests := []struct {
+		name     string
+		cs       tls.ConnectionState
+		insecure bool
+		pin      *x509.Certificate
+		wantErr  bool
+	}{
+		{
+			name: "verified leaf",
+			cs:   tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf, ca}, VerifiedChains: [][]*x509.Certificate{{leaf, ca}}},
+			pin:  leaf,
+		},
+		{
+			name: "verified CA",
+			cs:   tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf, ca}, VerifiedChains: [][]*x509.Certificate{{leaf, ca}}},
+			pin:  ca,
+		},
+		{
+			name:    "pinned certificate only sent, not verified",
+			cs:      tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf, pinned}, VerifiedChains: [][]*x509.Certificate{{leaf, ca}}},
+			pin:     pinned,
+			wantErr: true,
+		},
+		{
+			name:     "insecure leaf",
+			cs:       tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf, ca}},
+			insecure: true,
+			pin:      leaf,
+		},
+		{
+			name:     "insecure CA",
+			cs:       tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf, ca}},
+			insecure: true,
+			pin:      ca,
+			wantErr:  true,
+		},
+		{
+			name:     "insecure pinned certificate appended",
+			cs:       tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf, pinned}},
+			insecure: true,
+			pin:      pinned,
+			wantErr:  true,
+		},
+		{
+			name:    "no certificates",
+			cs:      tls.ConnectionState{},
+			pin:     leaf,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPins(pinCandidates(tt.cs, tt.insecure), [][]byte{pinOf(tt.pin)})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkPins() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && len(tt.cs.PeerCertificates) > 0 && !errors.Is(err, ErrPinMismatch) {
+				t.Errorf("checkPins() error = %v, want ErrPinMismatch", err)
+			}
+		})
+	}
+}
+
+func TestParsePin(t *testing.T) {
+	sum := sha256.Sum256([]byte("cert"))
+	hexPin := Fingerprint([]byte("cert"))
+
+	tests := []struct {
+		name    string
+		in      string
+		wantErr bool
+	}{
+		{name: "hex with colons", in: hexPin},
+		{name: "hex with prefix", in: "SHA256:" + hexPin},
+		{name: "base64", in: "sha256/" + base64.StdEncoding.EncodeToString(sum[:])},
+		{name: "too short", in: "00:11", wantErr: true},
+		{name: "garbage", in: "not a pin", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePin(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePin(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && string(got) != string(sum[:]) {
+				t.Errorf("ParsePin(%q) = %x, want %x", tt.in, got, sum)
+			}
+		})
+	}
+}
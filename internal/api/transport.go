@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// transport returns the client's own *http.Transport, cloning the default
+// transport on first use so options never modify http.DefaultTransport.
+func (c *Client) transport() *http.Transport {
+	if t, ok := c.httpClient.Transport.(*http.Transport); ok {
+		return t
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	c.httpClient.Transport = t
+	return t
+}
+
+// ProxyDirect is the proxy value that ignores the proxy environment variables.
+const ProxyDirect = "direct"
+
+// ParseProxy validates a proxy URL for WithProxy. Supported schemes are
+// http, https, socks5 and socks5h (DNS resolved by the proxy).
+func ParseProxy(s string) (*url.URL, error) {
+	if s == ProxyDirect {
+		return nil, nil
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", s, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https, socks5 or socks5h", s)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", s)
+	}
+	return u, nil
+}
+
+// WithProxy sends every request through proxy instead of the proxy named by
+// the HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment variables. A nil proxy
+// connects directly.
+func WithProxy(proxy *url.URL) Option {
+	return func(c *Client) {
+		if proxy == nil {
+			c.transport().Proxy = nil
+			return
+		}
+		c.transport().Proxy = http.ProxyURL(proxy)
+	}
+}
+
+// WithUnixSocket connects to the server through the Unix domain socket at
+// path. The server URL still supplies the scheme, Host header and path.
+func WithUnixSocket(path string) Option {
+	return func(c *Client) {
+		t := c.transport()
+		t.Proxy = nil
+		var d net.Dialer
+		t.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return d.DialContext(ctx, "unix", path)
+		}
+	}
+}
+
+// WithHeaders adds headers to every request, e.g. an API gateway key.
+func WithHeaders(h http.Header) Option {
+	return func(c *Client) { c.SetHeaders(h) }
+}
+
+// SetHeaders replaces the extra headers sent with every request. Call it
+// whenever the base URL changes so headers meant for one server are never
+// sent to another.
+func (c *Client) SetHeaders(h http.Header) {
+	h = h.Clone()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.headers = h
+}
+
+// ParseHeader parses a header given as "Name: value".
+func ParseHeader(s string) (name, value string, err error) {
+	name, value, ok := strings.Cut(s, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.ContainsAny(name, " \t") {
+		return "", "", fmt.Errorf("invalid header %q: want \"Name: value\"", s)
+	}
+	return name, strings.TrimSpace(value), nil
+}
+
+// joinURL resolves an API path such as "/api/v1/user/profile?x=1" against
+// the base URL, keeping any path prefix of the base URL
+// (https://gw.example.com/certvault + /api/v1/... → /certvault/api/v1/...).
+func joinURL(base, path string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid server URL %q: %w", base, err)
+	}
+	ref, err := url.Parse(path)
+	if err != nil {
+		return "", err
+	}
+	u = u.JoinPath(ref.EscapedPath())
+	u.RawQuery = ref.RawQuery
+	return u.String(), nil
+}
@@ -62,7 +62,7 @@ func (c *Client) LogoutAllSessions(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	c.session = ""
+	c.setSession("")
 	return nil
 }
 
@@ -211,7 +211,8 @@ func (c *Client) AnalyzePrivKey(ctx context.Context, privKey, password string) (
 	return &result.Data, nil
 }
 
-// ConvertPEMtoPFX converts a PEM cert+key to PFX format.
+// ConvertPEMtoPFX converts a PEM cert+key to PFX format. The certificate and
+// key in req are base64-encoded PEM; the result is base64-encoded PFX.
 func (c *Client) ConvertPEMtoPFX(ctx context.Context, req ConvertPEMtoPFXRequest) (*ConvertResult, error) {
 	resp, err := c.post(ctx, "/api/v1/user/cert/convert/pem/to/pfx", req)
 	if err != nil {
@@ -224,9 +225,10 @@ func (c *Client) ConvertPEMtoPFX(ctx context.Context, req ConvertPEMtoPFXRequest
 	return &result.Data, nil
 }
 
-// ConvertPEMtoDER converts a PEM certificate to DER format.
-func (c *Client) ConvertPEMtoDER(ctx context.Context, pem string) (*ConvertResult, error) {
-	resp, err := c.post(ctx, "/api/v1/user/cert/convert/pem/to/der", ConvertRequest{Cert: pem})
+// ConvertPEMtoDER converts a base64-encoded PEM certificate to DER format;
+// the result is base64-encoded DER.
+func (c *Client) ConvertPEMtoDER(ctx context.Context, cert string) (*ConvertResult, error) {
+	resp, err := c.post(ctx, "/api/v1/user/cert/convert/pem/to/der", ConvertRequest{Cert: cert})
 	if err != nil {
 		return nil, fmt.Errorf("convert PEM to DER: %w", err)
 	}
@@ -0,0 +1,181 @@
+// Package certfmt renders certificate and private key analyses as text for
+// the TUI and the CLI.
+package certfmt
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/theme"
+)
+
+// CertAnalysis renders a certificate analysis as styled text wrapped to
+// maxWidth columns.
+func CertAnalysis(a *api.CertAnalysis, maxWidth int) string {
+	sectionStyle := lipgloss.NewStyle().Foreground(theme.ColorPrimary).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(theme.ColorTextMuted)
+
+	const keyWidth = 19 // "Key:              " including trailing space
+	valueWidth := maxWidth - keyWidth
+	if valueWidth < 20 {
+		valueWidth = 20
+	}
+	indent := strings.Repeat(" ", keyWidth)
+
+	field := func(key, value string) string {
+		k := keyStyle.Render(fmt.Sprintf("%-18s", key+":"))
+		v := Wrap(value, valueWidth, indent)
+		return k + " " + v + "\n"
+	}
+	// expiryField colors only the Not After date.
+	expiryField := func(key, dateStr string) string {
+		k := keyStyle.Render(fmt.Sprintf("%-18s", key+":"))
+		daysLeft := api.DaysLeft(dateStr)
+		v := theme.ExpiryStyle(daysLeft).Render(dateStr)
+		return k + " " + v + "\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(sectionStyle.Render("Certificate Analysis"))
+	sb.WriteString("\n\n")
+
+	if a.Subject != "" {
+		sb.WriteString(field("Subject", a.Subject))
+	}
+	if a.Issuer != "" {
+		sb.WriteString(field("Issuer", a.Issuer))
+	}
+	sb.WriteString(field("Not Before", a.NotBefore))
+	sb.WriteString(expiryField("Not After", a.NotAfter))
+	if a.SerialNumber != "" {
+		sb.WriteString(field("Serial Number", a.SerialNumber))
+	}
+	if a.Fingerprint != "" {
+		sb.WriteString(field("Fingerprint", a.Fingerprint))
+	}
+	sb.WriteString(field("Is CA", boolStr(a.IsCA)))
+	sb.WriteString("\n")
+
+	// Public Key section
+	sb.WriteString(sectionStyle.Render("Public Key"))
+	sb.WriteString("\n\n")
+	sb.WriteString(field("Algorithm", a.Algorithm))
+	if len(a.PublicKey) > 0 {
+		keys := make([]string, 0, len(a.PublicKey))
+		for k := range a.PublicKey {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := a.PublicKey[k]
+			var vStr string
+			switch val := v.(type) {
+			case string:
+				vStr = val
+			case float64:
+				vStr = fmt.Sprintf("%.0f", val)
+			case bool:
+				vStr = boolStr(val)
+			default:
+				b, _ := json.Marshal(v)
+				vStr = string(b)
+			}
+			sb.WriteString(field(k, vStr))
+		}
+	}
+	sb.WriteString("\n")
+
+	// Extensions
+	if len(a.Extensions) > 0 {
+		sb.WriteString(sectionStyle.Render("Extensions"))
+		sb.WriteString("\n\n")
+		extKeys := make([]string, 0, len(a.Extensions))
+		for k := range a.Extensions {
+			extKeys = append(extKeys, k)
+		}
+		sort.Strings(extKeys)
+		for _, k := range extKeys {
+			sb.WriteString(field(k, a.Extensions[k]))
+		}
+		sb.WriteString("\n")
+	} else if len(a.SANs) > 0 {
+		sb.WriteString(sectionStyle.Render("Extensions"))
+		sb.WriteString("\n\n")
+		sb.WriteString(field("2.5.29.17", "SAN: "+strings.Join(a.SANs, ", ")))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// PrivKeyAnalysis renders a private key analysis as plain text.
+func PrivKeyAnalysis(a *api.PrivKeyAnalysis) string {
+	return "Algorithm: " + a.Algorithm + "\nKey Size: " + strconv.Itoa(a.KeySize) + " bits"
+}
+
+func boolStr(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
+
+// Wrap wraps a plain-text string so that each output line is at most maxWidth
+// rune-columns wide. Continuation lines are prefixed with indent.
+func Wrap(text string, maxWidth int, indent string) string {
+	if maxWidth <= 0 {
+		return text
+	}
+	runes := []rune(text)
+	if len(runes) <= maxWidth {
+		return text
+	}
+	indentRunes := []rune(indent)
+	// Continuation lines may be shorter due to indent; ensure minimum usable width.
+	contWidth := maxWidth - len(indentRunes)
+	if contWidth < 10 {
+		contWidth = maxWidth // indent too wide: fall back to maxWidth, no extra indent
+	}
+
+	breakAt := func(r []rune, width int) int {
+		if len(r) <= width {
+			return len(r)
+		}
+		// Search backwards from width-1 for a space to break on a word boundary.
+		w := width - 1
+		for w > 0 && r[w] != ' ' {
+			w--
+		}
+		if w == 0 {
+			return width // hard break: no space found within width
+		}
+		return w
+	}
+
+	var b strings.Builder
+	// First line.
+	cut := breakAt(runes, maxWidth)
+	b.WriteString(string(runes[:cut]))
+	rest := runes[cut:]
+	// Skip leading spaces on the remainder.
+	for len(rest) > 0 && rest[0] == ' ' {
+		rest = rest[1:]
+	}
+
+	for len(rest) > 0 {
+		b.WriteByte('\n')
+		b.WriteString(string(indentRunes))
+		cut = breakAt(rest, contWidth)
+		b.WriteString(string(rest[:cut]))
+		rest = rest[cut:]
+		for len(rest) > 0 && rest[0] == ' ' {
+			rest = rest[1:]
+		}
+	}
+	return b.String()
+}
@@ -2,8 +2,11 @@ package config
 
 import (
 	"encoding/json"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 )
 
 const (
@@ -15,68 +18,222 @@ const (
 // Config holds the application configuration.
 type Config struct {
 	ServerURL string `json:"server_url"`
-	Session   string `json:"session,omitempty"`
+	// Session is the session of ServerURL.
+	Session string `json:"session,omitempty"`
+	// Sessions maps other servers, by URL without a trailing slash, to their
+	// sessions, so a --server or CERTVAULT_URL server keeps its own.
+	Sessions map[string]string `json:"sessions,omitempty"`
+	// Retries is how often idempotent requests are retried; nil uses the default.
+	Retries *int `json:"retries,omitempty"`
+	// Timeout is the per-request timeout as a duration string such as "30s".
+	Timeout string `json:"timeout,omitempty"`
+	TLS     TLS    `json:"tls,omitzero"`
+	// Proxy is an http, https, socks5 or socks5h proxy URL, or "direct" to
+	// ignore the proxy environment variables.
+	Proxy string `json:"proxy,omitempty"`
+	// UnixSocket connects through a Unix domain socket instead of TCP.
+	UnixSocket string `json:"unix_socket,omitempty"`
+	// Headers maps a server URL to extra headers sent with every request to it.
+	Headers map[string]map[string]string `json:"headers,omitempty"`
+	Cache   Cache                        `json:"cache,omitzero"`
+
+	// savedServer and savedSession are server_url and session of the config
+	// file. ServerURL and Session differ from them while another server is
+	// used for one run.
+	savedServer  string
+	savedSession string
 }
 
-var configPath string
+// Cache holds the settings of the response cache used while the server is
+// unreachable.
+type Cache struct {
+	Disabled bool `json:"disabled,omitempty"`
+	// TTL is how long cached responses stay usable, as a duration string
+	// such as "72h"; empty means a week.
+	TTL string `json:"ttl,omitempty"`
+}
 
-func init() {
-	dir, err := os.UserConfigDir()
-	if err != nil {
-		dir = "."
+// HeadersFor returns the extra headers configured for server. A trailing
+// slash on either URL is ignored.
+func (c *Config) HeadersFor(server string) http.Header {
+	h := http.Header{}
+	for url, headers := range c.Headers {
+		if strings.TrimSuffix(url, "/") != strings.TrimSuffix(server, "/") {
+			continue
+		}
+		for name, value := range headers {
+			h.Set(name, value)
+		}
 	}
-	configPath = filepath.Join(dir, ConfigDirName, ConfigFileName)
+	return h
+}
+
+// TLS holds the TLS trust settings for HTTPS servers.
+type TLS struct {
+	CAFile     string   `json:"ca_file,omitempty"`
+	ClientCert string   `json:"client_cert,omitempty"`
+	ClientKey  string   `json:"client_key,omitempty"`
+	PinSHA256  []string `json:"pin_sha256,omitempty"`
+	Insecure   bool     `json:"insecure,omitempty"`
 }
 
 // Load reads config from disk (or returns defaults).
 func Load() (*Config, error) {
 	cfg := &Config{ServerURL: DefaultServerURL}
 
-	// Environment variable overrides
+	data, err := os.ReadFile(Path())
+	if err == nil {
+		err = json.Unmarshal(data, cfg)
+	} else if os.IsNotExist(err) {
+		err = nil
+	}
+	cfg.savedServer, cfg.savedSession = cfg.ServerURL, cfg.Session
+
+	// Environment variable overrides take priority over file
+	cfg.useEnv()
+	return cfg, err
+}
+
+// useEnv applies CERTVAULT_URL and CERTVAULT_SESSION.
+func (c *Config) useEnv() {
 	if url := os.Getenv("CERTVAULT_URL"); url != "" {
-		cfg.ServerURL = url
+		c.UseServer(url)
 	}
 	if session := os.Getenv("CERTVAULT_SESSION"); session != "" {
-		cfg.Session = session
+		c.Session = session
 	}
+}
 
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return cfg, nil
-		}
-		return cfg, err
+// UseServer switches to server for this run, e.g. for --server, with the
+// session saved for it. CERTVAULT_SESSION still takes priority. The config
+// file is left as is.
+func (c *Config) UseServer(server string) {
+	saveMu.Lock()
+	defer saveMu.Unlock()
+	c.ServerURL = server
+	if os.Getenv("CERTVAULT_SESSION") == "" {
+		c.Session = c.sessionOf(server)
+	}
+}
+
+// SetServer makes server the configured server, as the TUI does, switches to
+// its saved session and saves the config.
+func (c *Config) SetServer(server string) error {
+	saveMu.Lock()
+	defer saveMu.Unlock()
+	c.ServerURL = server
+	c.Session = c.sessionOf(server)
+	c.adopt()
+	return c.write()
+}
+
+// saveMu serializes Save, SetSession and the server changes, which run in
+// several goroutines of the TUI.
+var saveMu sync.Mutex
+
+// Save writes config to disk, making ServerURL the configured server. The
+// file is replaced atomically, so a process killed while saving leaves the
+// previous config intact.
+func Save(cfg *Config) error {
+	saveMu.Lock()
+	defer saveMu.Unlock()
+	cfg.adopt()
+	return cfg.write()
+}
+
+// SetSession stores session in cfg and saves it as the session of ServerURL.
+// The configured server_url is left as is; sessions of other servers are
+// saved in Sessions. A Config that was neither loaded nor saved, such as a
+// fallback for a broken config file, never overwrites the file.
+func (c *Config) SetSession(session string) error {
+	saveMu.Lock()
+	defer saveMu.Unlock()
+	c.Session = session
+	if c.savedServer == "" {
+		return nil
 	}
+	c.storeSession(c.ServerURL, session)
+	return c.write()
+}
 
-	if err := json.Unmarshal(data, cfg); err != nil {
-		return cfg, err
+// sessionOf returns the saved session of server.
+func (c *Config) sessionOf(server string) string {
+	if serverKey(server) == serverKey(c.savedServer) {
+		return c.savedSession
 	}
+	return c.Sessions[serverKey(server)]
+}
 
-	// Environment variable overrides take priority over file
-	if url := os.Getenv("CERTVAULT_URL"); url != "" {
-		cfg.ServerURL = url
+// storeSession records session as the saved session of server.
+func (c *Config) storeSession(server, session string) {
+	key := serverKey(server)
+	if key == serverKey(c.savedServer) {
+		c.savedSession = session
+		return
 	}
-	if session := os.Getenv("CERTVAULT_SESSION"); session != "" {
-		cfg.Session = session
+	if session == "" {
+		delete(c.Sessions, key)
+		return
+	}
+	if c.Sessions == nil {
+		c.Sessions = map[string]string{}
 	}
+	c.Sessions[key] = session
+}
 
-	return cfg, nil
+// adopt makes ServerURL and Session the saved server and session; the
+// session of the previous server moves to Sessions.
+func (c *Config) adopt() {
+	prevServer, prevSession := c.savedServer, c.savedSession
+	c.savedServer, c.savedSession = c.ServerURL, c.Session
+	delete(c.Sessions, serverKey(c.ServerURL))
+	if prevServer != "" && serverKey(prevServer) != serverKey(c.ServerURL) {
+		c.storeSession(prevServer, prevSession)
+	}
 }
 
-// Save writes config to disk.
-func Save(cfg *Config) error {
-	dir := filepath.Dir(configPath)
+// serverKey is the key of server in Sessions; a trailing slash is ignored.
+func serverKey(server string) string {
+	return strings.TrimSuffix(server, "/")
+}
+
+// write saves the file form of c, with the saved server and session.
+func (c *Config) write() error {
+	disk := *c
+	disk.ServerURL, disk.Session = c.savedServer, c.savedSession
+	path := Path()
+	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0700); err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	data, err := json.MarshalIndent(&disk, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(configPath, data, 0600)
+	f, err := os.CreateTemp(dir, ConfigFileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
 }
 
 // Path returns the configuration file path.
 func Path() string {
-	return configPath
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, ConfigDirName, ConfigFileName)
 }
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+const (
+	configured = "https://certvault.example"
+	other      = "https://other.certvault.example"
+)
+
+// isolate points the config file to a temporary directory and clears the
+// environment overrides.
+func isolate(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	for _, env := range []string{"CERTVAULT_URL", "CERTVAULT_SESSION"} {
+		t.Setenv(env, "")
+		os.Unsetenv(env)
+	}
+}
+
+// load calls Load and fails the test on errors.
+func load(t *testing.T) *Config {
+	t.Helper()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// saveConfigured writes a config file for configured with session.
+func saveConfigured(t *testing.T, session string) {
+	t.Helper()
+	if err := Save(&Config{ServerURL: configured, Session: session}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnvServerSessionIsSavedApart(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		file       bool
+		wantServer string
+	}{
+		{name: "no config file", wantServer: DefaultServerURL},
+		{name: "config file", file: true, wantServer: configured},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t)
+			if tt.file {
+				saveConfigured(t, "configured-session")
+			}
+
+			t.Setenv("CERTVAULT_URL", other)
+			if err := load(t).SetSession("other-session"); err != nil {
+				t.Fatal(err)
+			}
+			if got := load(t).Session; got != "other-session" {
+				t.Errorf("session with CERTVAULT_URL = %q, want other-session", got)
+			}
+
+			os.Unsetenv("CERTVAULT_URL")
+			saved := load(t)
+			if saved.ServerURL != tt.wantServer {
+				t.Errorf("server_url = %q, want %q", saved.ServerURL, tt.wantServer)
+			}
+			if tt.file && saved.Session != "configured-session" {
+				t.Errorf("session of the configured server = %q, want configured-session", saved.Session)
+			}
+			if got := saved.Sessions[other]; got != "other-session" {
+				t.Errorf("sessions[%q] = %q, want other-session", other, got)
+			}
+		})
+	}
+}
+
+func TestUseServerKeepsConfiguredSession(t *testing.T) {
+	isolate(t)
+	saveConfigured(t, "configured-session")
+
+	cfg := load(t)
+	cfg.UseServer(other + "/")
+	if cfg.Session != "" {
+		t.Errorf("session of a new server = %q, want none", cfg.Session)
+	}
+	if err := cfg.SetSession("other-session"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg = load(t)
+	if cfg.ServerURL != configured || cfg.Session != "configured-session" {
+		t.Errorf("saved server %q, session %q; want %q, configured-session", cfg.ServerURL, cfg.Session, configured)
+	}
+	cfg.UseServer(other)
+	if cfg.Session != "other-session" {
+		t.Errorf("session of %s = %q, want other-session", other, cfg.Session)
+	}
+}
+
+func TestUseServerPrefersEnvSession(t *testing.T) {
+	isolate(t)
+	saveConfigured(t, "configured-session")
+	t.Setenv("CERTVAULT_SESSION", "env-session")
+
+	cfg := load(t)
+	cfg.UseServer(other)
+	if cfg.Session != "env-session" {
+		t.Errorf("session = %q, want env-session", cfg.Session)
+	}
+}
+
+func TestSetServerSwitchesSessions(t *testing.T) {
+	isolate(t)
+	saveConfigured(t, "configured-session")
+
+	cfg := load(t)
+	if err := cfg.SetServer(other); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Session != "" {
+		t.Errorf("session after switching to a new server = %q, want none", cfg.Session)
+	}
+	if err := cfg.SetSession("other-session"); err != nil {
+		t.Fatal(err)
+	}
+	if saved := load(t); saved.ServerURL != other || saved.Session != "other-session" || saved.Sessions[configured] != "configured-session" {
+		t.Errorf("saved server %q, session %q, sessions %v", saved.ServerURL, saved.Session, saved.Sessions)
+	}
+
+	if err := cfg.SetServer(configured); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Session != "configured-session" {
+		t.Errorf("session after switching back = %q, want configured-session", cfg.Session)
+	}
+	if saved := load(t); saved.ServerURL != configured || saved.Sessions[other] != "other-session" || len(saved.Sessions) != 1 {
+		t.Errorf("saved server %q, sessions %v", saved.ServerURL, saved.Sessions)
+	}
+}
+
+func TestSetSessionOfFallbackConfigDoesNotWrite(t *testing.T) {
+	isolate(t)
+	cfg := &Config{ServerURL: DefaultServerURL}
+	if err := cfg.SetSession("s3cr3t"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(Path()); !os.IsNotExist(err) {
+		t.Errorf("config file written: %v", err)
+	}
+}
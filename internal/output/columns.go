@@ -0,0 +1,118 @@
+package output
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+// Column is one table/CSV column of type T.
+type Column[T any] struct {
+	Header string
+	Wide   bool // only shown with -o wide (CSV always includes it)
+	Value  func(T) string
+}
+
+// column is a Column with its type erased for the registry.
+type column struct {
+	header string
+	wide   bool
+	value  func(any) string
+}
+
+var registry = map[reflect.Type][]column{}
+
+// Register sets the table layout of T. Values of T and *T are both accepted
+// when printing.
+func Register[T any](cols ...Column[T]) {
+	erased := make([]column, len(cols))
+	for i, c := range cols {
+		value := c.Value
+		erased[i] = column{header: c.Header, wide: c.Wide, value: func(v any) string {
+			switch x := v.(type) {
+			case T:
+				return value(x)
+			case *T:
+				if x == nil {
+					return ""
+				}
+				return value(*x)
+			}
+			return ""
+		}}
+	}
+	registry[reflect.TypeFor[T]()] = erased
+}
+
+func columnsFor(t reflect.Type) ([]column, bool) {
+	cols, ok := registry[t]
+	return cols, ok
+}
+
+func boolStr(b bool) string { return fmt.Sprintf("%t", b) }
+
+func init() {
+	Register(
+		Column[api.SSLCert]{Header: "UUID", Value: func(c api.SSLCert) string { return c.UUID }},
+		Column[api.SSLCert]{Header: "CA UUID", Value: func(c api.SSLCert) string { return c.CaUUID }},
+		Column[api.SSLCert]{Header: "OWNER", Value: func(c api.SSLCert) string { return c.Owner }},
+		Column[api.SSLCert]{Header: "COMMENT", Value: func(c api.SSLCert) string { return c.Comment }},
+		Column[api.SSLCert]{Header: "NOT BEFORE", Wide: true, Value: func(c api.SSLCert) string { return c.NotBefore }},
+		Column[api.SSLCert]{Header: "NOT AFTER", Value: func(c api.SSLCert) string { return c.NotAfter }},
+		Column[api.SSLCert]{Header: "DAYS LEFT", Value: func(c api.SSLCert) string { return fmt.Sprint(api.DaysLeft(c.NotAfter)) }},
+		Column[api.SSLCert]{Header: "CREATED", Wide: true, Value: func(c api.SSLCert) string { return c.CreatedAt }},
+		Column[api.SSLCert]{Header: "MODIFIED", Wide: true, Value: func(c api.SSLCert) string { return c.ModifiedAt }},
+	)
+	Register(
+		Column[api.CACert]{Header: "UUID", Value: func(c api.CACert) string { return c.UUID }},
+		Column[api.CACert]{Header: "TYPE", Value: func(c api.CACert) string { return c.CAType() }},
+		Column[api.CACert]{Header: "PARENT", Value: func(c api.CACert) string { return c.ParentCa }},
+		Column[api.CACert]{Header: "OWNER", Value: func(c api.CACert) string { return c.Owner }},
+		Column[api.CACert]{Header: "COMMENT", Value: func(c api.CACert) string { return c.Comment }},
+		Column[api.CACert]{Header: "AVAILABLE", Value: func(c api.CACert) string { return boolStr(c.Available) }},
+		Column[api.CACert]{Header: "ALLOW SUB CA", Wide: true, Value: func(c api.CACert) string { return boolStr(c.AllowSubCa) }},
+		Column[api.CACert]{Header: "NOT BEFORE", Wide: true, Value: func(c api.CACert) string { return c.NotBefore }},
+		Column[api.CACert]{Header: "NOT AFTER", Value: func(c api.CACert) string { return c.NotAfter }},
+		Column[api.CACert]{Header: "DAYS LEFT", Value: func(c api.CACert) string { return fmt.Sprint(api.DaysLeft(c.NotAfter)) }},
+	)
+	Register(
+		Column[api.AdminUser]{Header: "USERNAME", Value: func(u api.AdminUser) string { return u.Username }},
+		Column[api.AdminUser]{Header: "DISPLAY NAME", Value: func(u api.AdminUser) string { return u.DisplayName }},
+		Column[api.AdminUser]{Header: "EMAIL", Value: func(u api.AdminUser) string { return u.Email }},
+		Column[api.AdminUser]{Header: "ROLE", Value: func(u api.AdminUser) string { return api.RoleName(u.Role) }},
+	)
+	Register(
+		Column[api.LoginRecord]{Header: "UUID", Value: func(s api.LoginRecord) string { return s.UUID }},
+		Column[api.LoginRecord]{Header: "USERNAME", Value: func(s api.LoginRecord) string { return s.Username }},
+		Column[api.LoginRecord]{Header: "IP", Value: func(s api.LoginRecord) string { return s.IPAddress }},
+		Column[api.LoginRecord]{Header: "REGION", Value: func(s api.LoginRecord) string { return s.Region }},
+		Column[api.LoginRecord]{Header: "PROVINCE", Wide: true, Value: func(s api.LoginRecord) string { return s.Province }},
+		Column[api.LoginRecord]{Header: "CITY", Wide: true, Value: func(s api.LoginRecord) string { return s.City }},
+		Column[api.LoginRecord]{Header: "BROWSER", Value: func(s api.LoginRecord) string {
+			if s.OS != "" {
+				return s.Browser + " / " + s.OS
+			}
+			return s.Browser
+		}},
+		Column[api.LoginRecord]{Header: "LOGIN TIME", Value: func(s api.LoginRecord) string { return s.LoginTime }},
+		Column[api.LoginRecord]{Header: "ONLINE", Value: func(s api.LoginRecord) string { return boolStr(s.IsOnline) }},
+	)
+	Register(
+		Column[api.CertAnalysis]{Header: "SUBJECT", Value: func(a api.CertAnalysis) string { return a.Subject }},
+		Column[api.CertAnalysis]{Header: "ISSUER", Value: func(a api.CertAnalysis) string { return a.Issuer }},
+		Column[api.CertAnalysis]{Header: "CA", Value: func(a api.CertAnalysis) string { return boolStr(a.IsCA) }},
+		Column[api.CertAnalysis]{Header: "ALGORITHM", Value: func(a api.CertAnalysis) string { return a.Algorithm }},
+		Column[api.CertAnalysis]{Header: "NOT BEFORE", Wide: true, Value: func(a api.CertAnalysis) string { return a.NotBefore }},
+		Column[api.CertAnalysis]{Header: "NOT AFTER", Value: func(a api.CertAnalysis) string { return a.NotAfter }},
+		Column[api.CertAnalysis]{Header: "DAYS LEFT", Value: func(a api.CertAnalysis) string { return fmt.Sprint(api.DaysLeft(a.NotAfter)) }},
+		Column[api.CertAnalysis]{Header: "SERIAL", Wide: true, Value: func(a api.CertAnalysis) string { return a.SerialNumber }},
+		Column[api.CertAnalysis]{Header: "FINGERPRINT", Wide: true, Value: func(a api.CertAnalysis) string { return a.Fingerprint }},
+		Column[api.CertAnalysis]{Header: "SANS", Wide: true, Value: func(a api.CertAnalysis) string { return strings.Join(a.SANs, ",") }},
+	)
+	Register(
+		Column[api.PrivKeyAnalysis]{Header: "ALGORITHM", Value: func(a api.PrivKeyAnalysis) string { return a.Algorithm }},
+		Column[api.PrivKeyAnalysis]{Header: "KEY SIZE", Value: func(a api.PrivKeyAnalysis) string { return fmt.Sprint(a.KeySize) }},
+	)
+}
@@ -0,0 +1,149 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// jsonStep is one step of a JSONPath expression.
+type jsonStep struct {
+	field    string // object key; empty for index steps
+	index    int
+	wildcard bool
+}
+
+// parseJSONPath parses the JSONPath subset supported by -o jsonpath:
+// an optional "{...}" wrapper and "$" root, followed by .field, ['field'],
+// [n] (negative counts from the end), [*] and .* steps. Field names are the
+// JSON names shown by -o json, e.g. {[*].notAfter} or {.uuid}.
+func parseJSONPath(expr string) ([]jsonStep, error) {
+	p := strings.TrimSpace(expr)
+	if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+		p = strings.TrimSpace(p[1 : len(p)-1])
+	}
+	p = strings.TrimPrefix(p, "$")
+	var steps []jsonStep
+	for p != "" {
+		switch {
+		case strings.HasPrefix(p, ".["):
+			p = p[1:]
+		case strings.HasPrefix(p, ".*"):
+			steps = append(steps, jsonStep{wildcard: true})
+			p = p[2:]
+		case p[0] == '.':
+			p = p[1:]
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			if end == 0 {
+				if p == "" {
+					// A lone "." selects the root.
+					return steps, nil
+				}
+				return nil, fmt.Errorf("jsonpath %q: empty field name", expr)
+			}
+			steps = append(steps, jsonStep{field: p[:end]})
+			p = p[end:]
+		case p[0] == '[':
+			end := strings.IndexByte(p, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath %q: missing ]", expr)
+			}
+			inner := strings.TrimSpace(p[1:end])
+			p = p[end+1:]
+			switch {
+			case inner == "*":
+				steps = append(steps, jsonStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				steps = append(steps, jsonStep{field: inner[1 : len(inner)-1]})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("jsonpath %q: invalid index %q", expr, inner)
+				}
+				steps = append(steps, jsonStep{index: n})
+			}
+		default:
+			return nil, fmt.Errorf("jsonpath %q: unexpected %q", expr, p)
+		}
+	}
+	return steps, nil
+}
+
+// evalJSONPath applies steps to a decoded JSON document. Missing fields and
+// out-of-range indexes yield no result rather than an error, so a path can
+// be applied to a mixed list.
+func evalJSONPath(doc any, steps []jsonStep) []any {
+	current := []any{doc}
+	for _, s := range steps {
+		var next []any
+		for _, v := range current {
+			switch x := v.(type) {
+			case map[string]any:
+				if s.wildcard {
+					// In key order, so the output does not change between runs.
+					for _, k := range slices.Sorted(maps.Keys(x)) {
+						next = append(next, x[k])
+					}
+				} else if child, ok := x[s.field]; ok && s.field != "" {
+					next = append(next, child)
+				}
+			case []any:
+				switch {
+				case s.wildcard:
+					next = append(next, x...)
+				case s.field == "":
+					i := s.index
+					if i < 0 {
+						i += len(x)
+					}
+					if i >= 0 && i < len(x) {
+						next = append(next, x[i])
+					}
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// printJSONPath prints each match on its own line: strings as-is, anything
+// else as compact JSON.
+func printJSONPath(w io.Writer, expr string, data any) error {
+	steps, err := parseJSONPath(expr)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+	for _, v := range evalJSONPath(doc, steps) {
+		line, ok := v.(string)
+		if !ok {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			line = string(b)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
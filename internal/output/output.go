@@ -0,0 +1,285 @@
+// Package output renders API data for the scripting commands. Every command
+// shares one --output flag whose value selects a Format.
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"gopkg.in/yaml.v3"
+)
+
+// Format names accepted by --output.
+const (
+	Table    = "table"
+	Wide     = "wide"
+	JSON     = "json"
+	YAML     = "yaml"
+	CSV      = "csv"
+	Template = "template"
+	JSONPath = "jsonpath"
+)
+
+// Format is a parsed --output value. Template and JSONPath carry an
+// expression after "=", e.g. "jsonpath={[*].uuid}".
+type Format struct {
+	Name string
+	Expr string
+}
+
+// Usage describes the accepted --output values for flag help.
+const Usage = "Output format: table, wide, json, yaml, csv, template=<go-template> or jsonpath=<expr>"
+
+// Parse parses an --output value.
+func Parse(s string) (Format, error) {
+	name, expr, hasExpr := strings.Cut(s, "=")
+	name = strings.ToLower(name)
+	switch name {
+	case "", Table:
+		return Format{Name: Table}, nil
+	case Wide, JSON, YAML, CSV:
+		if hasExpr {
+			return Format{}, fmt.Errorf("output format %q takes no expression", name)
+		}
+		return Format{Name: name}, nil
+	case Template, JSONPath:
+		if expr == "" {
+			return Format{}, fmt.Errorf("output format %q needs an expression, e.g. %s=...", name, name)
+		}
+		f := Format{Name: name, Expr: expr}
+		// Compile once here so mistakes are reported before any API call.
+		var err error
+		if name == Template {
+			_, err = newTemplate(expr)
+		} else {
+			_, err = parseJSONPath(expr)
+		}
+		if err != nil {
+			return Format{}, err
+		}
+		return f, nil
+	}
+	return Format{}, fmt.Errorf("unknown output format %q (want table, wide, json, yaml, csv, template=... or jsonpath=...)", s)
+}
+
+// Print writes data, a value or slice of a registered type, to w. The
+// structured formats print its View, which adds the computed columns.
+func Print(w io.Writer, f Format, data any) error {
+	switch f.Name {
+	case Table, Wide:
+		return printTable(w, data, f.Name == Wide)
+	case CSV:
+		return printCSV(w, data)
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(View(data))
+	case YAML:
+		return printYAML(w, View(data))
+	case Template:
+		return printTemplate(w, f.Expr, View(data))
+	case JSONPath:
+		return printJSONPath(w, f.Expr, View(data))
+	}
+	return fmt.Errorf("unknown output format %q", f.Name)
+}
+
+// items returns data as a slice of elements.
+func items(data any) []any {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice {
+		return []any{data}
+	}
+	out := make([]any, v.Len())
+	for i := range out {
+		out[i] = v.Index(i).Interface()
+	}
+	return out
+}
+
+// elemType returns the struct type behind data, which may be a value,
+// pointer or slice of either.
+func elemType(data any) reflect.Type {
+	t := reflect.TypeOf(data)
+	for t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Pointer) {
+		t = t.Elem()
+	}
+	return t
+}
+
+// rows renders every element of data with the columns registered for its type.
+func rows(data any, wide bool) ([]string, [][]string, error) {
+	cols, ok := columnsFor(elemType(data))
+	if !ok {
+		return nil, nil, fmt.Errorf("no table layout for %T; use -o json or yaml", data)
+	}
+	var headers []string
+	for _, c := range cols {
+		if !c.wide || wide {
+			headers = append(headers, c.header)
+		}
+	}
+	var out [][]string
+	for _, item := range items(data) {
+		row := make([]string, 0, len(headers))
+		for _, c := range cols {
+			if !c.wide || wide {
+				row = append(row, c.value(item))
+			}
+		}
+		out = append(out, row)
+	}
+	return headers, out, nil
+}
+
+func printTable(w io.Writer, data any, wide bool) error {
+	headers, rows, err := rows(data, wide)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// printCSV writes every column, including the wide ones, since CSV is read by programs.
+func printCSV(w io.Writer, data any) error {
+	headers, rows, err := rows(data, true)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(headers); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// printYAML writes data as YAML using its JSON field names and order, so
+// YAML and JSON output describe the same document.
+func printYAML(w io.Writer, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	node, err := jsonToNode(dec)
+	if err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// jsonToNode converts the next JSON value of dec to a YAML node, keeping key order.
+func jsonToNode(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if v == '{' {
+			node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		for dec.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)})
+			}
+			child, err := jsonToNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		if _, err := dec.Token(); err != nil { // closing delimiter
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(v.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(v)}, nil
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+}
+
+// templateFuncs are available to -o template, next to the fields of the
+// views (e.g. {{.DaysLeft}}) and the methods of the DTOs.
+var templateFuncs = template.FuncMap{
+	"daysLeft": api.DaysLeft,
+	"role":     api.RoleName,
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join": strings.Join,
+}
+
+func newTemplate(expr string) (*template.Template, error) {
+	t, err := template.New("output").Funcs(templateFuncs).Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	return t, nil
+}
+
+// printTemplate executes the template once per element, ending each with a
+// newline unless the template already does.
+func printTemplate(w io.Writer, expr string, data any) error {
+	t, err := newTemplate(expr)
+	if err != nil {
+		return err
+	}
+	for _, item := range items(data) {
+		// Pointers let templates call pointer methods such as CACert.CAType.
+		v := reflect.ValueOf(item)
+		if v.Kind() == reflect.Struct {
+			p := reflect.New(v.Type())
+			p.Elem().Set(v)
+			item = p.Interface()
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, item); err != nil {
+			return err
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api/fake"
+)
+
+// seededCAs returns the CAs of the demo data, as listed by the superadmin.
+func seededCAs(t *testing.T) []api.CACert {
+	t.Helper()
+	store := fake.NewStore()
+	if err := store.Seed(); err != nil {
+		t.Fatal(err)
+	}
+	sa := store.Client()
+	ctx := context.Background()
+	if err := sa.Login(ctx, fake.DefaultUsername, fake.DefaultPassword); err != nil {
+		t.Fatal(err)
+	}
+	page, err := sa.ListAdminCAs(ctx, 1, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.List) < 2 {
+		t.Fatalf("seeded %d CAs, want at least 2", len(page.List))
+	}
+	return page.List
+}
+
+// render runs Print and returns its lines.
+func render(t *testing.T, format string, data any) []string {
+	t.Helper()
+	f, err := Parse(format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Print(&buf, f, data); err != nil {
+		t.Fatal(err)
+	}
+	out := strings.TrimSuffix(buf.String(), "\n")
+	if out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+func TestJSONPath(t *testing.T) {
+	cas := seededCAs(t)
+	each := func(fn func(c api.CACert) string) []string {
+		var out []string
+		for _, c := range cas {
+			out = append(out, fn(c))
+		}
+		return out
+	}
+	first, last := cas[0], cas[len(cas)-1]
+
+	tests := []struct {
+		name string
+		expr string
+		want []string
+	}{
+		{name: "field of every element", expr: "{[*].uuid}", want: each(func(c api.CACert) string { return c.UUID })},
+		{name: "without braces", expr: "$[*].owner", want: each(func(c api.CACert) string { return c.Owner })},
+		{name: "computed CA type", expr: "{[*].caType}", want: each(func(c api.CACert) string { return c.CAType() })},
+		{name: "computed days left", expr: "{[0].daysLeft}", want: []string{fmt.Sprint(api.DaysLeft(first.NotAfter))}},
+		{name: "negative index", expr: "{[-1].comment}", want: []string{last.Comment}},
+		{name: "quoted field", expr: "{[0]['notAfter']}", want: []string{first.NotAfter}},
+		{name: "dot before bracket", expr: "{.[0].uuid}", want: []string{first.UUID}},
+		{name: "non-string as JSON", expr: "{[0].available}", want: []string{fmt.Sprint(first.Available)}},
+		{
+			name: "object wildcard in key order",
+			expr: "{[0].*}",
+			want: []string{
+				fmt.Sprint(first.AllowSubCa), fmt.Sprint(first.Available), first.CAType(), first.Comment,
+				fmt.Sprint(api.DaysLeft(first.NotAfter)), first.NotAfter, first.NotBefore, first.Owner, first.ParentCa, first.UUID,
+			},
+		},
+		{name: "missing field", expr: "{[*].nope}"},
+		{name: "index out of range", expr: "{[99].uuid}"},
+		{name: "field of an array", expr: "{.uuid}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := render(t, "jsonpath="+tt.expr, cas); !slices.Equal(got, tt.want) {
+				t.Errorf("jsonpath=%s printed %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathWildcardIsStable(t *testing.T) {
+	cas := seededCAs(t)
+	want := render(t, "jsonpath={[*].*}", cas)
+	for range 20 {
+		if got := render(t, "jsonpath={[*].*}", cas); !slices.Equal(got, want) {
+			t.Fatalf("jsonpath={[*].*} printed %q, then %q", want, got)
+		}
+	}
+}
+
+func TestParseJSONPathErrors(t *testing.T) {
+	for _, expr := range []string{"{[0}", "{[x]}", "{..uuid}", "{uuid}"} {
+		if _, err := Parse("jsonpath=" + expr); err == nil {
+			t.Errorf("Parse(jsonpath=%s) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestViews(t *testing.T) {
+	cas := seededCAs(t)
+	ca := cas[0]
+	cert := api.SSLCert{UUID: "c1", NotAfter: ca.NotAfter}
+
+	tests := []struct {
+		name string
+		data any
+		want map[string]any
+	}{
+		{name: "CA", data: &ca, want: map[string]any{"uuid": ca.UUID, "caType": ca.CAType(), "daysLeft": float64(api.DaysLeft(ca.NotAfter))}},
+		{name: "certificate", data: cert, want: map[string]any{"uuid": "c1", "daysLeft": float64(api.DaysLeft(ca.NotAfter))}},
+		{name: "analysis", data: api.CertAnalysis{NotAfter: ca.NotAfter}, want: map[string]any{"notAfter": ca.NotAfter, "daysLeft": float64(api.DaysLeft(ca.NotAfter))}},
+		{name: "no view", data: api.AdminUser{Username: "alice"}, want: map[string]any{"username": "alice"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]any
+			if err := json.Unmarshal([]byte(strings.Join(render(t, JSON, tt.data), "\n")), &got); err != nil {
+				t.Fatal(err)
+			}
+			for k, want := range tt.want {
+				if got[k] != want {
+					t.Errorf("%s = %v, want %v", k, got[k], want)
+				}
+			}
+			if _, ok := tt.want["daysLeft"]; !ok {
+				if _, ok := got["daysLeft"]; ok {
+					t.Error("unexpected daysLeft")
+				}
+			}
+		})
+	}
+
+	if got := render(t, JSON, []api.CACert(nil)); !slices.Equal(got, []string{"null"}) {
+		t.Errorf("nil list printed %q, want null", got)
+	}
+	if got := render(t, "template={{.CAType}}/{{.DaysLeft}}", ca); !slices.Equal(got, []string{fmt.Sprintf("%s/%d", ca.CAType(), api.DaysLeft(ca.NotAfter))}) {
+		t.Errorf("template printed %q", got)
+	}
+}
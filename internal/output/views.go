@@ -0,0 +1,79 @@
+package output
+
+import (
+	"reflect"
+
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+)
+
+// Views add the values computed for the table columns, such as the days
+// left, to the JSON, YAML, template and jsonpath output. A view embeds its
+// DTO, so the DTO's own fields keep their names and order.
+
+type sslCertView struct {
+	api.SSLCert
+	DaysLeft int `json:"daysLeft"`
+}
+
+type caCertView struct {
+	api.CACert
+	CAType   string `json:"caType"`
+	DaysLeft int    `json:"daysLeft"`
+}
+
+type certAnalysisView struct {
+	api.CertAnalysis
+	DaysLeft int `json:"daysLeft"`
+}
+
+var views = map[reflect.Type]func(any) any{}
+
+// RegisterView sets the view of T used by the structured formats. Values of
+// T and *T are both converted.
+func RegisterView[T, V any](view func(T) V) {
+	views[reflect.TypeFor[T]()] = func(v any) any {
+		switch x := v.(type) {
+		case T:
+			return view(x)
+		case *T:
+			if x == nil {
+				return v
+			}
+			return view(*x)
+		}
+		return v
+	}
+}
+
+// View returns data, a value, pointer or slice, with each element replaced
+// by its view. Data of a type without a view is returned as is.
+func View(data any) any {
+	view, ok := views[elemType(data)]
+	if !ok {
+		return data
+	}
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice {
+		return view(data)
+	}
+	if v.IsNil() {
+		return data
+	}
+	out := make([]any, v.Len())
+	for i := range out {
+		out[i] = view(v.Index(i).Interface())
+	}
+	return out
+}
+
+func init() {
+	RegisterView(func(c api.SSLCert) sslCertView {
+		return sslCertView{SSLCert: c, DaysLeft: api.DaysLeft(c.NotAfter)}
+	})
+	RegisterView(func(c api.CACert) caCertView {
+		return caCertView{CACert: c, CAType: c.CAType(), DaysLeft: api.DaysLeft(c.NotAfter)}
+	})
+	RegisterView(func(a api.CertAnalysis) certAnalysisView {
+		return certAnalysisView{CertAnalysis: a, DaysLeft: api.DaysLeft(a.NotAfter)}
+	})
+}
@@ -0,0 +1,50 @@
+// Package theme holds the colors shared by the TUI and the styled text the
+// CLI prints, so neither output depends on the other.
+package theme
+
+import "github.com/charmbracelet/lipgloss"
+
+// Colors
+const (
+	ColorPrimary   = lipgloss.Color("#7C3AED")
+	ColorSecondary = lipgloss.Color("#A78BFA")
+	ColorSuccess   = lipgloss.Color("#10B981")
+	ColorWarning   = lipgloss.Color("#F59E0B")
+	ColorDanger    = lipgloss.Color("#EF4444")
+	ColorMuted     = lipgloss.Color("#6B7280")
+	ColorBg        = lipgloss.Color("#1E1B2E")
+	ColorSurface   = lipgloss.Color("#2D2B3D")
+	ColorBorder    = lipgloss.Color("#4C4A6B")
+	ColorText      = lipgloss.Color("#E2E8F0")
+	ColorTextMuted = lipgloss.Color("#94A3B8")
+	ColorHighlight = lipgloss.Color("#EDE9FE")
+	ColorWhite     = lipgloss.Color("#FFFFFF")
+)
+
+// Status styles
+var (
+	SuccessStyle = lipgloss.NewStyle().
+			Foreground(ColorSuccess).
+			Bold(true)
+
+	WarningStyle = lipgloss.NewStyle().
+			Foreground(ColorWarning).
+			Bold(true)
+
+	DangerStyle = lipgloss.NewStyle().
+			Foreground(ColorDanger).
+			Bold(true)
+)
+
+// ExpiryStyle returns a color-coded style for a certificate expiry date.
+// Green (>30 days), Yellow (<30 days), Red (expired).
+func ExpiryStyle(daysLeft int) lipgloss.Style {
+	switch {
+	case daysLeft < 0:
+		return DangerStyle
+	case daysLeft < 30:
+		return WarningStyle
+	default:
+		return SuccessStyle
+	}
+}
@@ -3,6 +3,7 @@ package tui
 import (
 	"context"
 	"fmt"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -34,9 +35,10 @@ const (
 
 // App is the main Bubble Tea application model.
 type App struct {
-	client       *api.Client
+	client       api.Service
 	cfg          *config.Config
 	profile      *api.UserProfile
+	caps         api.Capabilities
 	view         ViewID
 	prevView     ViewID
 	width        int
@@ -63,17 +65,49 @@ type App struct {
 	adminView      *views.Admin
 	superadminView *views.Superadmin
 	settingsView   *views.Settings
+
+	// traces records the last debugTraces API requests from the start, so
+	// the panel opened after a failure shows the failed request.
+	traces     *api.TraceRing
+	debugPanel *views.DebugPanel
+}
+
+// offlineReporter is implemented by clients that answer from a local cache
+// while the server is unreachable, such as cache.Service.
+type offlineReporter interface {
+	Offline() (saved time.Time, offline bool)
 }
 
+// offlineLabel describes the cached data on screen, or is "" when online.
+func (a *App) offlineLabel() string {
+	r, ok := a.client.(offlineReporter)
+	if !ok {
+		return ""
+	}
+	saved, offline := r.Offline()
+	if !offline {
+		return ""
+	}
+	return "⚠ OFFLINE · cached " + saved.Local().Format("Jan 2 15:04") + " · read-only"
+}
+
+// debugTraces is the number of requests kept for the debug panel.
+const debugTraces = 50
+
 // NewApp creates a new App.
-func NewApp(client *api.Client, cfg *config.Config) *App {
+func NewApp(client api.Service, cfg *config.Config) *App {
 	loginView := views.NewLogin(client, cfg)
+	traces := api.NewTraceRing(debugTraces)
+	// Until the panel is first opened only summaries are recorded, so the
+	// bodies are not read and redacted on every request.
+	client.OnTraceSummary(traces.Add, traces.Detailed)
 	return &App{
 		client:    client,
 		cfg:       cfg,
 		view:      ViewLogin,
 		loginView: &loginView,
 		help:      components.NewHelp(components.DefaultEntries()),
+		traces:    traces,
 	}
 }
 
@@ -92,7 +126,7 @@ func (a *App) tryAutoLogin() tea.Cmd {
 		if err != nil {
 			return nil // session invalid; stay on login screen
 		}
-		return views.LoginSuccessMsg{Profile: profile}
+		return views.LoginSuccessMsg{Profile: profile, Caps: a.client.Probe(context.Background())}
 	}
 }
 
@@ -107,10 +141,6 @@ func (a *App) doLogout() tea.Cmd {
 // resetToLogin clears session state and switches to the login view.
 func (a *App) resetToLogin() tea.Cmd {
 	a.client.SetSession("")
-	if a.cfg != nil {
-		a.cfg.Session = ""
-		_ = config.Save(a.cfg)
-	}
 	a.profile = nil
 	loginView := views.NewLogin(a.client, a.cfg)
 	a.loginView = &loginView
@@ -120,8 +150,21 @@ func (a *App) resetToLogin() tea.Cmd {
 	return a.loginView.Init()
 }
 
-// Update handles all messages.
+// Update handles all messages. Requests still running in a view the user
+// leaves are cancelled so their results are never applied.
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	from := a.view
+	model, cmd := a.update(msg)
+	if a.view != from {
+		a.cancelView(from)
+		if a.view == ViewDashboard && from != ViewLogin && a.dashboardView != nil {
+			cmd = tea.Batch(cmd, a.dashboardView.Resume())
+		}
+	}
+	return model, cmd
+}
+
+func (a *App) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		a.width = msg.Width
@@ -138,6 +181,24 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.String() == "ctrl+q" {
 			return a, tea.Quit
 		}
+		// Toggle the debug panel; while open it receives every key.
+		if msg.String() == "f12" || msg.String() == "ctrl+g" {
+			if a.debugPanel != nil {
+				a.debugPanel = nil
+			} else {
+				a.traces.SetDetailed()
+				panel := views.NewDebugPanel(a.traces)
+				a.debugPanel = &panel
+				a.updateSizes()
+			}
+			return a, nil
+		}
+		if a.debugPanel != nil {
+			if a.debugPanel.Update(msg) {
+				a.debugPanel = nil
+			}
+			return a, nil
+		}
 		// Toggle help
 		if msg.String() == "?" {
 			a.help.Toggle()
@@ -168,9 +229,14 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return a, nil // re-render with updated dialog state (e.g. left/right key)
 		}
+		// Esc aborts the running request before it is used to navigate.
+		if msg.String() == "esc" && a.cancelView(a.view) {
+			return a, a.toast.Show("cancelled", components.ToastInfo)
+		}
 
 	case views.LoginSuccessMsg:
 		a.profile = msg.Profile
+		a.caps = msg.Caps
 		return a, a.switchToMain()
 
 	case views.SessionExpiredMsg:
@@ -217,7 +283,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "enter":
 				ca := a.caListView.SelectedCA()
 				if ca != nil {
-					d := views.NewCADetail(ca, a.client, false)
+					d := views.NewCADetail(ca, a.client, false, a.caps)
 					a.caDetailView = &d
 					a.prevView = ViewCAList
 					a.view = ViewCADetail
@@ -365,6 +431,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmd, urlUpdated = a.settingsView.Update(msg)
 			if urlUpdated {
 				a.client.SetBaseURL(a.cfg.ServerURL)
+				a.client.SetHeaders(a.cfg.HeadersFor(a.cfg.ServerURL))
 			}
 		}
 	}
@@ -372,6 +439,64 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return a, cmd
 }
 
+// cancelView aborts the requests running in the given view and reports
+// whether one of its spinner operations was interrupted.
+func (a *App) cancelView(id ViewID) bool {
+	switch id {
+	case ViewLogin:
+		return a.loginView.Cancel()
+	case ViewDashboard:
+		if a.dashboardView != nil {
+			return a.dashboardView.Cancel()
+		}
+	case ViewCAList:
+		if a.caListView != nil {
+			return a.caListView.Cancel()
+		}
+	case ViewCADetail:
+		if a.caDetailView != nil {
+			return a.caDetailView.Cancel()
+		}
+	case ViewCertList:
+		if a.certListView != nil {
+			return a.certListView.Cancel()
+		}
+	case ViewCertDetail:
+		if a.certDetailView != nil {
+			return a.certDetailView.Cancel()
+		}
+	case ViewCertRequest:
+		if a.certReqView != nil {
+			return a.certReqView.Cancel()
+		}
+	case ViewCARequest:
+		if a.caReqView != nil {
+			return a.caReqView.Cancel()
+		}
+	case ViewProfile:
+		if a.profileView != nil {
+			return a.profileView.Cancel()
+		}
+	case ViewSessions:
+		if a.sessionsView != nil {
+			return a.sessionsView.Cancel()
+		}
+	case ViewTools:
+		if a.toolsView != nil {
+			return a.toolsView.Cancel()
+		}
+	case ViewAdmin:
+		if a.adminView != nil {
+			return a.adminView.Cancel()
+		}
+	case ViewSuperadmin:
+		if a.superadminView != nil {
+			return a.superadminView.Cancel()
+		}
+	}
+	return false
+}
+
 // handleSidebar processes sidebar key events.
 func (a *App) handleSidebar(msg tea.Msg) tea.Cmd {
 	key, ok := msg.(tea.KeyMsg)
@@ -393,6 +518,9 @@ func (a *App) handleSidebar(msg tea.Msg) tea.Cmd {
 
 func (a *App) navigateToSidebarItem() tea.Cmd {
 	id := a.sidebar.SelectedID()
+	if item := a.sidebar.Selected(); item != nil && item.Disabled != "" {
+		return a.toast.Show(item.Disabled, components.ToastError)
+	}
 	switch id {
 	case "dashboard":
 		a.view = ViewDashboard
@@ -437,7 +565,7 @@ func (a *App) navigateToSidebarItem() tea.Cmd {
 }
 
 func (a *App) switchToMain() tea.Cmd {
-	dashView := views.NewDashboard(a.client, a.profile)
+	dashView := views.NewDashboard(a.client, a.profile, a.caps)
 	a.dashboardView = &dashView
 
 	caList := views.NewCAList(a.client)
@@ -461,10 +589,10 @@ func (a *App) switchToMain() tea.Cmd {
 	toolsView := views.NewTools(a.client)
 	a.toolsView = &toolsView
 
-	adminView := views.NewAdmin(a.client)
+	adminView := views.NewAdmin(a.client, a.caps)
 	a.adminView = &adminView
 
-	superadminView := views.NewSuperadmin(a.client)
+	superadminView := views.NewSuperadmin(a.client, a.caps)
 	a.superadminView = &superadminView
 
 	settingsView := views.NewSettings(a.cfg)
@@ -503,8 +631,8 @@ func (a *App) buildSidebarItems() []components.SidebarItem {
 		{Icon: "📜", Label: "SSL Certificates", ID: "cert_list"},
 		{Icon: "➕", Label: "Request Cert", ID: "cert_request"},
 		{Icon: "👤", Label: "Profile", ID: "profile"},
-		{Icon: "📋", Label: "Sessions", ID: "sessions"},
-		{Icon: "🛠", Label: "Tools", ID: "tools"},
+		{Icon: "📋", Label: "Sessions", ID: "sessions", Disabled: a.unsupported(api.CapSessions)},
+		{Icon: "🛠", Label: "Tools", ID: "tools", Disabled: a.unsupported(api.CapTools)},
 	}
 
 	if a.profile != nil && a.profile.Role >= 2 {
@@ -520,6 +648,14 @@ func (a *App) buildSidebarItems() []components.SidebarItem {
 	return items
 }
 
+// unsupported explains why the server lacks c, or is "" if it has it.
+func (a *App) unsupported(c api.Capability) string {
+	if err := a.caps.Check(c); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
 func (a *App) updateSizes() {
 	if !a.ready {
 		return
@@ -532,6 +668,13 @@ func (a *App) updateSizes() {
 	a.sidebar.SetSize(sidebarWidth, contentHeight)
 	a.statusBar.SetSize(a.width)
 	a.help.SetSize(a.width)
+	if a.debugPanel != nil {
+		if a.view == ViewLogin {
+			a.debugPanel.SetSize(a.width, a.height-2)
+		} else {
+			a.debugPanel.SetSize(contentWidth, contentHeight)
+		}
+	}
 
 	if a.loginView != nil {
 		a.loginView.SetSize(a.width, a.height)
@@ -579,6 +722,12 @@ func (a *App) View() string {
 
 	if a.view == ViewLogin {
 		view := a.loginView.View()
+		if a.debugPanel != nil {
+			view = a.debugPanel.View()
+		}
+		if a.toast.IsVisible() {
+			view += "\n" + a.toast.View()
+		}
 		if a.help.IsVisible() {
 			return view + "\n" + a.help.View()
 		}
@@ -592,6 +741,9 @@ func (a *App) View() string {
 	if a.logoutDialog != nil {
 		contentView = a.logoutDialog.View(a.width - 22)
 	}
+	if a.debugPanel != nil {
+		contentView = a.debugPanel.View()
+	}
 
 	sidebarWidth := 22
 	contentWidth := a.width - sidebarWidth
@@ -602,17 +754,20 @@ func (a *App) View() string {
 	content := lipgloss.NewStyle().Width(contentWidth).Render(contentView)
 	mainArea := lipgloss.JoinHorizontal(lipgloss.Top, sidebarView, content)
 
-	statusBar := a.statusBar.View()
+	statusBar := a.statusBar
+	statusBar.Offline = a.offlineLabel()
 
 	var footer string
 	if a.help.IsVisible() {
 		helpView := a.help.View()
 		footer = lipgloss.PlaceHorizontal(a.width, lipgloss.Center, helpView)
+	} else if a.toast.IsVisible() {
+		footer = a.toast.View()
 	} else {
 		footer = HelpStyle.Render("? help • ctrl+q quit • ctrl+l logout")
 	}
 
-	return fmt.Sprintf("%s\n%s\n%s", mainArea, statusBar, footer)
+	return fmt.Sprintf("%s\n%s\n%s", mainArea, statusBar.View(), footer)
 }
 
 func (a *App) currentContentView() string {
@@ -674,6 +829,6 @@ func (a *App) currentContentView() string {
 }
 
 // Client returns the API client.
-func (a *App) Client() *api.Client {
+func (a *App) Client() api.Service {
 	return a.client
 }
@@ -50,13 +50,14 @@ func DefaultEntries() []HelpEntry {
 		{Key: "scroll/drag", Desc: "Mouse wheel navigation"},
 		{Key: "[/]", Desc: "Prev/next API page"},
 		{Key: "enter", Desc: "Select / confirm"},
-		{Key: "esc", Desc: "Back / cancel"},
+		{Key: "esc", Desc: "Back / cancel (aborts a running request)"},
 		{Key: "r/F5", Desc: "Refresh"},
 		{Key: "n", Desc: "New item"},
 		{Key: "d", Desc: "Delete item"},
 		{Key: "x", Desc: "Export"},
 		{Key: "tab", Desc: "Next field"},
 		{Key: "?", Desc: "Toggle help"},
+		{Key: "F12/ctrl+g", Desc: "Toggle API request debug panel"},
 		{Key: "q", Desc: "Quit (from list/detail views)"},
 		{Key: "ctrl+c", Desc: "Force quit"},
 		{Key: "L (sidebar)", Desc: "Logout with confirmation"},
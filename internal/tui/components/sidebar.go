@@ -13,6 +13,9 @@ type SidebarItem struct {
 	Label    string
 	ID       string
 	Children []SidebarItem
+	// Disabled, when set, explains why the item cannot be opened; it is
+	// shown dimmed.
+	Disabled string
 }
 
 // Sidebar is the navigation sidebar component.
@@ -51,6 +54,14 @@ func (s *Sidebar) SelectedID() string {
 	return s.Items[s.cursor].ID
 }
 
+// Selected returns the currently selected item, or nil if there is none.
+func (s *Sidebar) Selected() *SidebarItem {
+	if s.cursor < 0 || s.cursor >= len(s.Items) {
+		return nil
+	}
+	return &s.Items[s.cursor]
+}
+
 // SelectedIndex returns the cursor index.
 func (s *Sidebar) SelectedIndex() int {
 	return s.cursor
@@ -105,6 +116,8 @@ func (s *Sidebar) View() string {
 		var line string
 		if s.focused && i == s.cursor {
 			line = st.SidebarSelectedStyle.Width(itemWidth).Render(label)
+		} else if item.Disabled != "" {
+			line = st.SidebarItemStyle.Foreground(st.ColorMuted).Width(itemWidth).Render(label)
 		} else {
 			line = st.SidebarItemStyle.Width(itemWidth).Render(label)
 		}
@@ -1,16 +1,21 @@
 package components
 
 import (
+	"context"
+
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	st "github.com/gregPerlinLi/CertVaultCLIX/internal/tui/styles"
 )
 
-// Spinner is a loading indicator.
+// Spinner is a loading indicator. It also owns the context of the requests
+// started through Run, so that cancelling the spinner aborts them.
 type Spinner struct {
 	model   spinner.Model
 	active  bool
 	message string
+	ctx     context.Context
+	cancel  context.CancelFunc
 }
 
 // NewSpinner creates a new spinner.
@@ -33,6 +38,35 @@ func (s *Spinner) Stop() {
 	s.active = false
 }
 
+// Run returns a command calling fn with the spinner's current context. If the
+// context is cancelled before fn returns, its message is dropped so that the
+// result of an abandoned request never reaches the view.
+func (s *Spinner) Run(fn func(ctx context.Context) tea.Msg) tea.Cmd {
+	if s.ctx == nil {
+		s.ctx, s.cancel = context.WithCancel(context.Background())
+	}
+	ctx := s.ctx
+	return func() tea.Msg {
+		msg := fn(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		return msg
+	}
+}
+
+// Cancel aborts every request started through Run and deactivates the
+// spinner. It reports whether the spinner was active.
+func (s *Spinner) Cancel() bool {
+	if s.cancel != nil {
+		s.cancel()
+		s.ctx, s.cancel = nil, nil
+	}
+	active := s.active
+	s.active = false
+	return active
+}
+
 // IsActive returns whether the spinner is active.
 func (s *Spinner) IsActive() bool {
 	return s.active
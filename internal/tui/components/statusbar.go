@@ -15,7 +15,9 @@ type StatusBar struct {
 	RoleInt  int // numeric role for color coding
 	Server   string
 	Status   string
-	width    int
+	// Offline, when set, is shown as a warning that the data is cached.
+	Offline string
+	width   int
 }
 
 // SetSize sets the status bar width.
@@ -41,6 +43,9 @@ func (s *StatusBar) View() string {
 	if s.Status != "" {
 		mid = st.StatusBarStyle.Render(fmt.Sprintf("  %s  ", s.Status))
 	}
+	if s.Offline != "" {
+		mid = st.StatusBarWarning.Render(s.Offline) + mid
+	}
 
 	leftWidth := lipgloss.Width(left)
 	rightWidth := lipgloss.Width(right)
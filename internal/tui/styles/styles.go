@@ -1,22 +1,26 @@
 package styles
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/theme"
+)
 
-// Theme colors
+// Theme colors, defined in package theme so certfmt shares them without
+// depending on the TUI.
 const (
-	ColorPrimary   = lipgloss.Color("#7C3AED")
-	ColorSecondary = lipgloss.Color("#A78BFA")
-	ColorSuccess   = lipgloss.Color("#10B981")
-	ColorWarning   = lipgloss.Color("#F59E0B")
-	ColorDanger    = lipgloss.Color("#EF4444")
-	ColorMuted     = lipgloss.Color("#6B7280")
-	ColorBg        = lipgloss.Color("#1E1B2E")
-	ColorSurface   = lipgloss.Color("#2D2B3D")
-	ColorBorder    = lipgloss.Color("#4C4A6B")
-	ColorText      = lipgloss.Color("#E2E8F0")
-	ColorTextMuted = lipgloss.Color("#94A3B8")
-	ColorHighlight = lipgloss.Color("#EDE9FE")
-	ColorWhite     = lipgloss.Color("#FFFFFF")
+	ColorPrimary   = theme.ColorPrimary
+	ColorSecondary = theme.ColorSecondary
+	ColorSuccess   = theme.ColorSuccess
+	ColorWarning   = theme.ColorWarning
+	ColorDanger    = theme.ColorDanger
+	ColorMuted     = theme.ColorMuted
+	ColorBg        = theme.ColorBg
+	ColorSurface   = theme.ColorSurface
+	ColorBorder    = theme.ColorBorder
+	ColorText      = theme.ColorText
+	ColorTextMuted = theme.ColorTextMuted
+	ColorHighlight = theme.ColorHighlight
+	ColorWhite     = theme.ColorWhite
 )
 
 // Styles are the application-wide lipgloss styles.
@@ -44,17 +48,9 @@ var (
 			Foreground(ColorTextMuted).
 			Padding(0, 1)
 
-	SuccessStyle = lipgloss.NewStyle().
-			Foreground(ColorSuccess).
-			Bold(true)
-
-	WarningStyle = lipgloss.NewStyle().
-			Foreground(ColorWarning).
-			Bold(true)
-
-	DangerStyle = lipgloss.NewStyle().
-			Foreground(ColorDanger).
-			Bold(true)
+	SuccessStyle = theme.SuccessStyle
+	WarningStyle = theme.WarningStyle
+	DangerStyle  = theme.DangerStyle
 
 	BorderStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
@@ -77,6 +73,12 @@ var (
 				Bold(true).
 				Padding(0, 1)
 
+	StatusBarWarning = lipgloss.NewStyle().
+				Background(ColorWarning).
+				Foreground(ColorBg).
+				Bold(true).
+				Padding(0, 1)
+
 	HelpStyle = lipgloss.NewStyle().
 			Foreground(ColorTextMuted)
 
@@ -166,14 +168,7 @@ var (
 // ExpiryStyle returns a color-coded style for a certificate expiry date.
 // Green (>30 days), Yellow (<30 days), Red (expired).
 func ExpiryStyle(daysLeft int) lipgloss.Style {
-	switch {
-	case daysLeft < 0:
-		return DangerStyle
-	case daysLeft < 30:
-		return WarningStyle
-	default:
-		return SuccessStyle
-	}
+	return theme.ExpiryStyle(daysLeft)
 }
 
 // RoleStyle returns a color-coded style for a user role.
@@ -31,7 +31,8 @@ type AdminDataMsg struct {
 
 // Admin is the admin management view.
 type Admin struct {
-	client       *api.Client
+	client       api.Service
+	caps         api.Capabilities
 	mode         AdminMode
 	menuIdx      int
 	table        components.Table
@@ -52,8 +53,8 @@ var adminMenuItems = []string{
 	"CA Management",
 }
 
-// NewAdmin creates a new admin view.
-func NewAdmin(client *api.Client) Admin {
+// NewAdmin creates a new admin view for a server supporting caps.
+func NewAdmin(client api.Service, caps api.Capabilities) Admin {
 	cols := []components.Column{
 		{Title: "Username", Width: 20},
 		{Title: "Display Name", Width: 25},
@@ -62,12 +63,19 @@ func NewAdmin(client *api.Client) Admin {
 	}
 	return Admin{
 		client:  client,
+		caps:    caps,
 		table:   components.NewTable(cols, 15),
 		page:    1,
 		spinner: components.NewSpinner(),
 	}
 }
 
+// Cancel aborts the view's running requests and reports whether a
+// spinner operation was interrupted.
+func (a *Admin) Cancel() bool {
+	return a.spinner.Cancel()
+}
+
 // SetSize updates dimensions.
 func (a *Admin) SetSize(width, height int) {
 	a.width = width
@@ -99,7 +107,7 @@ func (a *Admin) Update(msg tea.Msg) tea.Cmd {
 			if isUnauthorized(msg.Err) {
 				return func() tea.Msg { return SessionExpiredMsg{} }
 			}
-			a.err = msg.Err.Error()
+			a.err = errorText(msg.Err)
 			return nil
 		}
 		a.users = msg.Users
@@ -169,7 +177,7 @@ func (a *Admin) Update(msg tea.Msg) tea.Cmd {
 				if a.mode == AdminModeCAs {
 					idx := a.table.SelectedIndex()
 					if idx >= 0 && idx < len(a.cas) {
-						d := NewCADetail(&a.cas[idx], a.client, true)
+						d := NewCADetail(&a.cas[idx], a.client, true, a.caps)
 						a.caDetailView = &d
 						a.mode = AdminModeCADetail
 					}
@@ -189,8 +197,7 @@ func (a *Admin) Update(msg tea.Msg) tea.Cmd {
 func (a *Admin) load() tea.Cmd {
 	mode := a.mode
 	page := a.page
-	return func() tea.Msg {
-		ctx := context.Background()
+	return a.spinner.Run(func(ctx context.Context) tea.Msg {
 		switch mode {
 		case AdminModeUsers:
 			users, err := a.client.ListAdminUsers(ctx, page, 20)
@@ -206,7 +213,7 @@ func (a *Admin) load() tea.Cmd {
 			return AdminDataMsg{CAs: cas.List, Total: cas.Total}
 		}
 		return AdminDataMsg{}
-	}
+	})
 }
 
 func (a *Admin) buildUserRows() []components.Row {
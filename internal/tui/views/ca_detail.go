@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/certfmt"
 	"github.com/gregPerlinLi/CertVaultCLIX/internal/tui/components"
 	tui "github.com/gregPerlinLi/CertVaultCLIX/internal/tui/styles"
 )
@@ -41,8 +42,9 @@ const (
 // CADetail shows detailed information about a CA certificate.
 type CADetail struct {
 	CA          *api.CACert
-	client      *api.Client
+	client      api.Service
 	isAdmin     bool // use admin API for fetching cert
+	caps        api.Capabilities
 	mode        caDetailMode
 	spinner     components.Spinner
 	resultVP    viewport.Model
@@ -78,7 +80,8 @@ type CADetail struct {
 
 // NewCADetail creates a new CA detail view.
 // Pass isAdmin=true when used inside the Admin view (uses admin API endpoints).
-func NewCADetail(ca *api.CACert, client *api.Client, isAdmin bool) CADetail {
+// caps disables binding users when the server cannot.
+func NewCADetail(ca *api.CACert, client api.Service, isAdmin bool, caps api.Capabilities) CADetail {
 	vp := viewport.New(80, 20)
 	ei := components.NewPathInput("e.g. /home/user/ca.pem", 512)
 	pi := textinput.New()
@@ -100,6 +103,7 @@ func NewCADetail(ca *api.CACert, client *api.Client, isAdmin bool) CADetail {
 		CA:           ca,
 		client:       client,
 		isAdmin:      isAdmin,
+		caps:         caps,
 		spinner:      components.NewSpinner(),
 		resultVP:     vp,
 		exportInput:  ei,
@@ -112,6 +116,12 @@ func NewCADetail(ca *api.CACert, client *api.Client, isAdmin bool) CADetail {
 	}
 }
 
+// Cancel aborts the view's running requests and reports whether a
+// spinner operation was interrupted.
+func (c *CADetail) Cancel() bool {
+	return c.spinner.Cancel()
+}
+
 // SetSize updates dimensions.
 func (c *CADetail) SetSize(width, height int) {
 	c.width = width
@@ -309,14 +319,14 @@ func (c *CADetail) Update(msg tea.Msg) tea.Cmd {
 				c.chainSelIdx = 0
 				c.mode = caDetailChainSel
 			case "b":
-				if c.isAdmin {
+				if c.isAdmin && c.caps.Has(api.CapCABindings) {
 					c.mode = caDetailBindSel
 					c.unboundPage = 1
 					c.bindMsg = ""
 					return c.loadUnboundUsers()
 				}
 			case "u":
-				if c.isAdmin {
+				if c.isAdmin && c.caps.Has(api.CapCABindings) {
 					c.mode = caDetailBoundList
 					c.boundPage = 1
 					return c.loadBoundUsers()
@@ -475,8 +485,7 @@ func (c *CADetail) fetchAndViewCert(chain, needRoot bool) tea.Cmd {
 	client := c.client
 	isAdmin := c.isAdmin
 	spinCmd := c.spinner.Start("Fetching certificate...")
-	return tea.Batch(spinCmd, func() tea.Msg {
-		ctx := context.Background()
+	return tea.Batch(spinCmd, c.spinner.Run(func(ctx context.Context) tea.Msg {
 		var encoded string
 		var err error
 		if isAdmin {
@@ -485,14 +494,14 @@ func (c *CADetail) fetchAndViewCert(chain, needRoot bool) tea.Cmd {
 			encoded, err = client.GetUserCACert(ctx, uuid, chain, needRoot)
 		}
 		if err != nil {
-			return certContentMsg{err: err.Error()}
+			return certContentMsg{err: errorText(err)}
 		}
 		decoded, decErr := base64.StdEncoding.DecodeString(encoded)
 		if decErr != nil {
 			return certContentMsg{err: "decode error: " + decErr.Error()}
 		}
 		return certContentMsg{content: string(decoded)}
-	})
+	}))
 }
 
 func (c *CADetail) doExportCA() tea.Cmd {
@@ -517,8 +526,7 @@ func (c *CADetail) doExportCA() tea.Cmd {
 	client := c.client
 	isAdmin := c.isAdmin
 	spinCmd := c.spinner.Start("Exporting...")
-	return tea.Batch(spinCmd, func() tea.Msg {
-		ctx := context.Background()
+	return tea.Batch(spinCmd, c.spinner.Run(func(ctx context.Context) tea.Msg {
 		var encoded string
 		var err error
 		if isAdmin {
@@ -527,7 +535,7 @@ func (c *CADetail) doExportCA() tea.Cmd {
 			encoded, err = client.GetUserCACert(ctx, uuid, opt.chain, opt.needRoot)
 		}
 		if err != nil {
-			return certContentMsg{err: err.Error()}
+			return certContentMsg{err: errorText(err)}
 		}
 		decoded, decErr := base64.StdEncoding.DecodeString(encoded)
 		if decErr != nil {
@@ -537,7 +545,7 @@ func (c *CADetail) doExportCA() tea.Cmd {
 			return certContentMsg{err: writeErr.Error()}
 		}
 		return certExportedMsg{path: path}
-	})
+	}))
 }
 
 func (c *CADetail) startAnalysis() tea.Cmd {
@@ -546,8 +554,7 @@ func (c *CADetail) startAnalysis() tea.Cmd {
 	isAdmin := c.isAdmin
 	vpWidth := c.resultVP.Width
 	spinCmd := c.spinner.Start("Analyzing...")
-	return tea.Batch(spinCmd, func() tea.Msg {
-		ctx := context.Background()
+	return tea.Batch(spinCmd, c.spinner.Run(func(ctx context.Context) tea.Msg {
 		var certPEM string
 		var err error
 		if isAdmin {
@@ -556,14 +563,14 @@ func (c *CADetail) startAnalysis() tea.Cmd {
 			certPEM, err = client.GetUserCACert(ctx, uuid, false, false)
 		}
 		if err != nil {
-			return inlineAnalysisMsg{err: err.Error()}
+			return inlineAnalysisMsg{err: errorText(err)}
 		}
 		analysis, err := client.AnalyzeCert(ctx, certPEM)
 		if err != nil {
-			return inlineAnalysisMsg{err: err.Error()}
+			return inlineAnalysisMsg{err: errorText(err)}
 		}
-		return inlineAnalysisMsg{result: formatCertAnalysis(analysis, vpWidth)}
-	})
+		return inlineAnalysisMsg{result: certfmt.CertAnalysis(analysis, vpWidth)}
+	}))
 }
 
 func (c *CADetail) fetchPrivKey() tea.Cmd {
@@ -573,17 +580,17 @@ func (c *CADetail) fetchPrivKey() tea.Cmd {
 	c.passInput.Blur()
 	c.passInput.SetValue("")
 	spinCmd := c.spinner.Start("Fetching private key...")
-	return tea.Batch(spinCmd, func() tea.Msg {
-		resp, err := client.GetAdminCAPrivKey(context.Background(), uuid, password)
+	return tea.Batch(spinCmd, c.spinner.Run(func(ctx context.Context) tea.Msg {
+		resp, err := client.GetAdminCAPrivKey(ctx, uuid, password)
 		if err != nil {
-			return certPrivKeyMsg{err: err.Error()}
+			return certPrivKeyMsg{err: errorText(err)}
 		}
 		decoded, decErr := base64.StdEncoding.DecodeString(resp)
 		if decErr != nil {
 			return certPrivKeyMsg{err: "decode error: " + decErr.Error()}
 		}
 		return certPrivKeyMsg{content: string(decoded)}
-	})
+	}))
 }
 
 func (c *CADetail) doExportPrivKey() tea.Cmd {
@@ -607,26 +614,26 @@ func (c *CADetail) doBindUser(username string) tea.Cmd {
 	uuid := c.CA.UUID
 	client := c.client
 	spinCmd := c.spinner.Start("Binding user...")
-	return tea.Batch(spinCmd, func() tea.Msg {
-		err := client.BindUsersToCA(context.Background(), uuid, []string{username})
+	return tea.Batch(spinCmd, c.spinner.Run(func(ctx context.Context) tea.Msg {
+		err := client.BindUsersToCA(ctx, uuid, []string{username})
 		if err != nil {
-			return caBindMsg{err: err.Error()}
+			return caBindMsg{err: errorText(err)}
 		}
 		return caBindMsg{}
-	})
+	}))
 }
 
 func (c *CADetail) doUnbindUser(username string) tea.Cmd {
 	uuid := c.CA.UUID
 	client := c.client
 	spinCmd := c.spinner.Start("Unbinding user...")
-	return tea.Batch(spinCmd, func() tea.Msg {
-		err := client.UnbindUsersFromCA(context.Background(), uuid, []string{username})
+	return tea.Batch(spinCmd, c.spinner.Run(func(ctx context.Context) tea.Msg {
+		err := client.UnbindUsersFromCA(ctx, uuid, []string{username})
 		if err != nil {
-			return caUnbindMsg{err: err.Error()}
+			return caUnbindMsg{err: errorText(err)}
 		}
 		return caUnbindMsg{}
-	})
+	}))
 }
 
 func (c *CADetail) loadBoundUsers() tea.Cmd {
@@ -634,13 +641,13 @@ func (c *CADetail) loadBoundUsers() tea.Cmd {
 	page := c.boundPage
 	client := c.client
 	spinCmd := c.spinner.Start("Loading bound users...")
-	return tea.Batch(spinCmd, func() tea.Msg {
-		result, err := client.GetBoundUsers(context.Background(), uuid, page, 20)
+	return tea.Batch(spinCmd, c.spinner.Run(func(ctx context.Context) tea.Msg {
+		result, err := client.GetBoundUsers(ctx, uuid, page, 20)
 		if err != nil {
-			return caBoundUsersMsg{err: err.Error()}
+			return caBoundUsersMsg{err: errorText(err)}
 		}
 		return caBoundUsersMsg{users: result.List, total: result.Total}
-	})
+	}))
 }
 
 func (c *CADetail) loadUnboundUsers() tea.Cmd {
@@ -648,13 +655,13 @@ func (c *CADetail) loadUnboundUsers() tea.Cmd {
 	page := c.unboundPage
 	client := c.client
 	spinCmd := c.spinner.Start("Loading unbound users...")
-	return tea.Batch(spinCmd, func() tea.Msg {
-		result, err := client.GetUnboundUsers(context.Background(), uuid, page, 20)
+	return tea.Batch(spinCmd, c.spinner.Run(func(ctx context.Context) tea.Msg {
+		result, err := client.GetUnboundUsers(ctx, uuid, page, 20)
 		if err != nil {
-			return caUnboundUsersMsg{err: err.Error()}
+			return caUnboundUsersMsg{err: errorText(err)}
 		}
 		return caUnboundUsersMsg{users: result.List, total: result.Total}
-	})
+	}))
 }
 
 // View renders the CA detail.
@@ -727,6 +734,11 @@ func (c *CADetail) View() string {
 	helpKeys := "a: analyze • v: view cert • e: export cert • esc: back"
 	if c.isAdmin {
 		helpKeys = "a: analyze • v: view cert • e: export cert • k: view privkey • K: export privkey • b: bind user • u: bound users • esc: back"
+		if err := c.caps.Check(api.CapCABindings); err != nil {
+			helpKeys = "a: analyze • v: view cert • e: export cert • k: view privkey • K: export privkey • esc: back"
+			sb.WriteString(tui.MutedStyle.Render(err.Error() + "."))
+			sb.WriteString("\n")
+		}
 	}
 	sb.WriteString(tui.HelpStyle.Render(helpKeys))
 	return sb.String()
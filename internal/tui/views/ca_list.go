@@ -20,7 +20,7 @@ type CAListLoadedMsg struct {
 
 // CAList is the CA certificate list view.
 type CAList struct {
-	client  *api.Client
+	client  api.UserService
 	table   components.Table
 	cas     []api.CACert
 	total   int64
@@ -33,7 +33,7 @@ type CAList struct {
 }
 
 // NewCAList creates a new CA list view.
-func NewCAList(client *api.Client) CAList {
+func NewCAList(client api.UserService) CAList {
 	cols := []components.Column{
 		{Title: "Comment", Width: 28},
 		{Title: "Owner", Width: 12},
@@ -50,6 +50,12 @@ func NewCAList(client *api.Client) CAList {
 	}
 }
 
+// Cancel aborts the view's running requests and reports whether a
+// spinner operation was interrupted.
+func (c *CAList) Cancel() bool {
+	return c.spinner.Cancel()
+}
+
 // SetSize updates dimensions.
 func (c *CAList) SetSize(width, height int) {
 	c.width = width
@@ -64,13 +70,13 @@ func (c *CAList) Init() tea.Cmd {
 }
 
 func (c *CAList) load() tea.Cmd {
-	return func() tea.Msg {
-		cas, err := c.client.ListUserCAs(context.Background(), c.page, 20)
+	return c.spinner.Run(func(ctx context.Context) tea.Msg {
+		cas, err := c.client.ListUserCAs(ctx, c.page, 20)
 		if err != nil {
 			return CAListLoadedMsg{Err: err}
 		}
 		return CAListLoadedMsg{CAs: cas.List, Total: cas.Total}
-	}
+	})
 }
 
 // SelectedCA returns the currently selected CA or nil.
@@ -91,7 +97,7 @@ func (c *CAList) Update(msg tea.Msg) tea.Cmd {
 			if isUnauthorized(msg.Err) {
 				return func() tea.Msg { return SessionExpiredMsg{} }
 			}
-			c.err = msg.Err.Error()
+			c.err = errorText(msg.Err)
 			return nil
 		}
 		c.cas = msg.CAs
@@ -28,7 +28,7 @@ type caReqCAsMsg struct {
 // CARequest is the form for requesting a new CA certificate.
 // It uses a viewport so the form is always scrollable — even in small terminals.
 type CARequest struct {
-	client   *api.Client
+	client   api.AdminService
 	fields   []*components.FormField
 	form     components.Form
 	viewport viewport.Model
@@ -46,7 +46,7 @@ type CARequest struct {
 }
 
 // NewCARequest creates a new CA request form.
-func NewCARequest(client *api.Client) CARequest {
+func NewCARequest(client api.AdminService) CARequest {
 	fields := []*components.FormField{
 		{Label: "Parent CA (↑/↓ to select)", Placeholder: "Loading CAs..."},
 		{Label: "Allow Sub-CA (↑=true/↓=false/space: toggle)", Placeholder: ""},
@@ -77,6 +77,12 @@ func NewCARequest(client *api.Client) CARequest {
 	return r
 }
 
+// Cancel aborts the view's running requests and reports whether a
+// spinner operation was interrupted.
+func (c *CARequest) Cancel() bool {
+	return c.spinner.Cancel()
+}
+
 // SetSize updates dimensions.
 func (c *CARequest) SetSize(width, height int) {
 	c.width = width
@@ -109,13 +115,13 @@ func (c *CARequest) Init() tea.Cmd {
 // fetchCAs loads all CA certificates (admin view) for the parent selector.
 func (c *CARequest) fetchCAs() tea.Cmd {
 	client := c.client
-	return func() tea.Msg {
-		page, err := client.ListAdminCAs(context.Background(), 1, 200)
+	return c.spinner.Run(func(ctx context.Context) tea.Msg {
+		cas, err := api.CollectPages(ctx, client.ListAdminCAs, api.PageOptions{Prefetch: true})
 		if err != nil {
 			return caReqCAsMsg{err: err}
 		}
-		return caReqCAsMsg{cas: page.List}
-	}
+		return caReqCAsMsg{cas: cas}
+	})
 }
 
 // refreshViewport scrolls to the focused field.
@@ -279,7 +285,7 @@ func (c *CARequest) Update(msg tea.Msg) tea.Cmd {
 			if isUnauthorized(msg.Err) {
 				return func() tea.Msg { return SessionExpiredMsg{} }
 			}
-			c.err = msg.Err.Error()
+			c.err = errorText(msg.Err)
 		}
 		return nil
 	}
@@ -338,10 +344,10 @@ func (c *CARequest) submit() tea.Cmd {
 	}
 
 	cmd := c.spinner.Start("Requesting CA certificate...")
-	return tea.Batch(cmd, func() tea.Msg {
-		ca, err := c.client.RequestAdminCA(context.Background(), req)
+	return tea.Batch(cmd, c.spinner.Run(func(ctx context.Context) tea.Msg {
+		ca, err := c.client.RequestAdminCA(ctx, req)
 		return CARequestedMsg{CA: ca, Err: err}
-	})
+	}))
 }
 
 // View renders the CA request form inside a viewport.
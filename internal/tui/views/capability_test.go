@@ -0,0 +1,96 @@
+package views
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api/fake"
+)
+
+// superadmin returns a client logged in as the superadmin of a demo server
+// lacking without, and the capabilities it probed.
+func superadmin(t *testing.T, without ...api.Capability) (api.Service, api.Capabilities) {
+	t.Helper()
+	store := fake.NewStore()
+	if err := store.Seed(); err != nil {
+		t.Fatal(err)
+	}
+	store.Without(without...)
+	client := store.Client()
+	ctx := context.Background()
+	if err := client.Login(ctx, fake.DefaultUsername, fake.DefaultPassword); err != nil {
+		t.Fatal(err)
+	}
+	return client, client.Probe(ctx)
+}
+
+func key(s string) tea.KeyMsg {
+	if s == "enter" {
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestCADetailBindings(t *testing.T) {
+	for _, supported := range []bool{true, false} {
+		var without []api.Capability
+		if !supported {
+			without = append(without, api.CapCABindings)
+		}
+		client, caps := superadmin(t, without...)
+		cas, err := client.ListAdminCAs(context.Background(), 1, 1)
+		if err != nil || len(cas.List) == 0 {
+			t.Fatalf("ListAdminCAs() = %v, %v", cas, err)
+		}
+
+		for k, help := range map[string]string{"b": "b: bind user", "u": "u: bound users"} {
+			d := NewCADetail(&cas.List[0], client, true, caps)
+			cmd := d.Update(key(k))
+			if opened := d.mode != caDetailNormal; opened != supported || (cmd != nil) != supported {
+				t.Errorf("supported=%v: key %s opened=%v, cmd=%v", supported, k, opened, cmd != nil)
+			}
+			d.mode = caDetailNormal
+			view := d.View()
+			if listed := strings.Contains(view, help); listed != supported {
+				t.Errorf("supported=%v: help lists %q: %v", supported, help, listed)
+			}
+			if explained := strings.Contains(view, "not supported"); explained == supported {
+				t.Errorf("supported=%v: view explains the missing bindings: %v", supported, explained)
+			}
+		}
+	}
+}
+
+func TestSuperadminSessions(t *testing.T) {
+	for _, supported := range []bool{true, false} {
+		var without []api.Capability
+		if !supported {
+			without = append(without, api.CapSessions)
+		}
+		client, caps := superadmin(t, without...)
+
+		s := NewSuperadmin(client, caps)
+		if explained := strings.Contains(s.View(), "not supported"); explained == supported {
+			t.Errorf("supported=%v: menu explains the missing sessions: %v", supported, explained)
+		}
+		cmd := s.Update(key("enter"))
+		if opened := s.mode == SuperadminModeSessions; opened != supported || (cmd != nil) != supported {
+			t.Errorf("supported=%v: All Sessions opened=%v, cmd=%v", supported, opened, cmd != nil)
+		}
+
+		s = NewSuperadmin(client, caps)
+		s.mode = SuperadminModeUserDetail
+		s.selectedUser = &api.AdminUser{Username: "alice"}
+		s.userDetailMenuIdx = 4
+		if explained := strings.Contains(s.View(), "not supported"); explained == supported {
+			t.Errorf("supported=%v: user menu explains the missing sessions: %v", supported, explained)
+		}
+		cmd = s.Update(key("enter"))
+		if opened := s.mode == SuperadminModeUserSessions; opened != supported || (cmd != nil) != supported {
+			t.Errorf("supported=%v: View Sessions opened=%v, cmd=%v", supported, opened, cmd != nil)
+		}
+	}
+}
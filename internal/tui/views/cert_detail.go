@@ -12,6 +12,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/certfmt"
 	"github.com/gregPerlinLi/CertVaultCLIX/internal/tui/components"
 	tui "github.com/gregPerlinLi/CertVaultCLIX/internal/tui/styles"
 )
@@ -54,7 +55,7 @@ const (
 // CertDetail shows detailed information about an SSL certificate.
 type CertDetail struct {
 	Cert        *api.SSLCert
-	client      *api.Client
+	client      api.UserService
 	mode        certDetailMode
 	spinner     components.Spinner
 	resultVP    viewport.Model
@@ -78,7 +79,7 @@ type CertDetail struct {
 }
 
 // NewCertDetail creates a new SSL cert detail view.
-func NewCertDetail(cert *api.SSLCert, client *api.Client) CertDetail {
+func NewCertDetail(cert *api.SSLCert, client api.UserService) CertDetail {
 	vp := viewport.New(80, 20)
 	ei := components.NewPathInput("e.g. /home/user/cert.pem", 512)
 	pi := textinput.New()
@@ -97,6 +98,12 @@ func NewCertDetail(cert *api.SSLCert, client *api.Client) CertDetail {
 	}
 }
 
+// Cancel aborts the view's running requests and reports whether a
+// spinner operation was interrupted.
+func (c *CertDetail) Cancel() bool {
+	return c.spinner.Cancel()
+}
+
 // SetSize updates dimensions.
 func (c *CertDetail) SetSize(width, height int) {
 	c.width = width
@@ -345,18 +352,17 @@ func (c *CertDetail) fetchAndViewCert(chain, needRoot bool) tea.Cmd {
 	uuid := c.Cert.UUID
 	client := c.client
 	spinCmd := c.spinner.Start("Fetching certificate...")
-	return tea.Batch(spinCmd, func() tea.Msg {
-		ctx := context.Background()
+	return tea.Batch(spinCmd, c.spinner.Run(func(ctx context.Context) tea.Msg {
 		encoded, err := client.GetUserSSLCert(ctx, uuid, chain, needRoot)
 		if err != nil {
-			return certContentMsg{err: err.Error()}
+			return certContentMsg{err: errorText(err)}
 		}
 		decoded, err := base64.StdEncoding.DecodeString(encoded)
 		if err != nil {
 			return certContentMsg{err: "decode error: " + err.Error()}
 		}
 		return certContentMsg{content: string(decoded)}
-	})
+	}))
 }
 
 func (c *CertDetail) fetchPrivKey() tea.Cmd {
@@ -366,17 +372,17 @@ func (c *CertDetail) fetchPrivKey() tea.Cmd {
 	c.passInput.Blur()
 	c.passInput.SetValue("")
 	spinCmd := c.spinner.Start("Fetching private key...")
-	return tea.Batch(spinCmd, func() tea.Msg {
-		resp, err := client.GetUserSSLPrivKey(context.Background(), uuid, password)
+	return tea.Batch(spinCmd, c.spinner.Run(func(ctx context.Context) tea.Msg {
+		resp, err := client.GetUserSSLPrivKey(ctx, uuid, password)
 		if err != nil {
-			return certPrivKeyMsg{err: err.Error()}
+			return certPrivKeyMsg{err: errorText(err)}
 		}
 		decoded, decErr := base64.StdEncoding.DecodeString(resp)
 		if decErr != nil {
 			return certPrivKeyMsg{err: "decode error: " + decErr.Error()}
 		}
 		return certPrivKeyMsg{content: string(decoded)}
-	})
+	}))
 }
 
 func (c *CertDetail) doExport() tea.Cmd {
@@ -402,11 +408,10 @@ func (c *CertDetail) doExport() tea.Cmd {
 	uuid := c.Cert.UUID
 	client := c.client
 	spinCmd := c.spinner.Start("Exporting...")
-	return tea.Batch(spinCmd, func() tea.Msg {
-		ctx := context.Background()
+	return tea.Batch(spinCmd, c.spinner.Run(func(ctx context.Context) tea.Msg {
 		encoded, err := client.GetUserSSLCert(ctx, uuid, opt.chain, opt.needRoot)
 		if err != nil {
-			return certContentMsg{err: err.Error()}
+			return certContentMsg{err: errorText(err)}
 		}
 		decoded, decErr := base64.StdEncoding.DecodeString(encoded)
 		if decErr != nil {
@@ -416,7 +421,7 @@ func (c *CertDetail) doExport() tea.Cmd {
 			return certContentMsg{err: writeErr.Error()}
 		}
 		return certExportedMsg{path: path}
-	})
+	}))
 }
 
 func (c *CertDetail) doExportPrivKey() tea.Cmd {
@@ -449,18 +454,17 @@ func (c *CertDetail) startAnalysis() tea.Cmd {
 	client := c.client
 	vpWidth := c.resultVP.Width
 	spinCmd := c.spinner.Start("Analyzing...")
-	return tea.Batch(spinCmd, func() tea.Msg {
-		ctx := context.Background()
+	return tea.Batch(spinCmd, c.spinner.Run(func(ctx context.Context) tea.Msg {
 		certPEM, err := client.GetUserSSLCert(ctx, uuid, false, false)
 		if err != nil {
-			return inlineAnalysisMsg{err: err.Error()}
+			return inlineAnalysisMsg{err: errorText(err)}
 		}
 		analysis, err := client.AnalyzeCert(ctx, certPEM)
 		if err != nil {
-			return inlineAnalysisMsg{err: err.Error()}
+			return inlineAnalysisMsg{err: errorText(err)}
 		}
-		return inlineAnalysisMsg{result: formatCertAnalysis(analysis, vpWidth)}
-	})
+		return inlineAnalysisMsg{result: certfmt.CertAnalysis(analysis, vpWidth)}
+	}))
 }
 
 // View renders the cert detail.
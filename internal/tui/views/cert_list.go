@@ -20,7 +20,7 @@ type CertListLoadedMsg struct {
 
 // CertList is the SSL certificate list view.
 type CertList struct {
-	client  *api.Client
+	client  api.UserService
 	table   components.Table
 	certs   []api.SSLCert
 	total   int64
@@ -33,7 +33,7 @@ type CertList struct {
 }
 
 // NewCertList creates a new SSL cert list view.
-func NewCertList(client *api.Client) CertList {
+func NewCertList(client api.UserService) CertList {
 	cols := []components.Column{
 		{Title: "Comment", Width: 28},
 		{Title: "Owner", Width: 15},
@@ -48,6 +48,12 @@ func NewCertList(client *api.Client) CertList {
 	}
 }
 
+// Cancel aborts the view's running requests and reports whether a
+// spinner operation was interrupted.
+func (c *CertList) Cancel() bool {
+	return c.spinner.Cancel()
+}
+
 // SetSize updates dimensions.
 func (c *CertList) SetSize(width, height int) {
 	c.width = width
@@ -62,13 +68,13 @@ func (c *CertList) Init() tea.Cmd {
 }
 
 func (c *CertList) load() tea.Cmd {
-	return func() tea.Msg {
-		certs, err := c.client.ListUserSSLCerts(context.Background(), c.page, 20)
+	return c.spinner.Run(func(ctx context.Context) tea.Msg {
+		certs, err := c.client.ListUserSSLCerts(ctx, c.page, 20)
 		if err != nil {
 			return CertListLoadedMsg{Err: err}
 		}
 		return CertListLoadedMsg{Certs: certs.List, Total: certs.Total}
-	}
+	})
 }
 
 // SelectedCert returns the currently selected cert.
@@ -89,7 +95,7 @@ func (c *CertList) Update(msg tea.Msg) tea.Cmd {
 			if isUnauthorized(msg.Err) {
 				return func() tea.Msg { return SessionExpiredMsg{} }
 			}
-			c.err = msg.Err.Error()
+			c.err = errorText(msg.Err)
 			return nil
 		}
 		c.certs = msg.Certs
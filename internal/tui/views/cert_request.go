@@ -28,7 +28,7 @@ type certReqCAsMsg struct {
 // CertRequest is the form for requesting a new SSL certificate.
 // It uses a viewport so the form is always scrollable — even in small terminals.
 type CertRequest struct {
-	client   *api.Client
+	client   api.UserService
 	fields   []*components.FormField
 	form     components.Form
 	viewport viewport.Model
@@ -50,7 +50,7 @@ const linesPerField = 3 // label + input + blank line
 const formTitleLines = 2 // title + blank line
 
 // NewCertRequest creates a new cert request form.
-func NewCertRequest(client *api.Client) CertRequest {
+func NewCertRequest(client api.UserService) CertRequest {
 	fields := []*components.FormField{
 		{Label: "CA (↑/↓ to select)", Placeholder: "Loading available CAs..."},
 		{Label: "Common Name (CN)", Placeholder: "e.g. example.com"},
@@ -80,6 +80,12 @@ func NewCertRequest(client *api.Client) CertRequest {
 	return r
 }
 
+// Cancel aborts the view's running requests and reports whether a
+// spinner operation was interrupted.
+func (c *CertRequest) Cancel() bool {
+	return c.spinner.Cancel()
+}
+
 // SetSize updates dimensions.
 func (c *CertRequest) SetSize(width, height int) {
 	c.width = width
@@ -107,16 +113,16 @@ func (c *CertRequest) Init() tea.Cmd {
 	return tea.Batch(textinput.Blink, c.fetchCAs())
 }
 
-// fetchCAs loads all available CAs (up to 200) for the selector.
+// fetchCAs loads every available CA for the selector.
 func (c *CertRequest) fetchCAs() tea.Cmd {
 	client := c.client
-	return func() tea.Msg {
-		page, err := client.ListUserCAs(context.Background(), 1, 200)
+	return c.spinner.Run(func(ctx context.Context) tea.Msg {
+		cas, err := api.CollectPages(ctx, client.ListUserCAs, api.PageOptions{Prefetch: true})
 		if err != nil {
 			return certReqCAsMsg{err: err}
 		}
-		return certReqCAsMsg{cas: page.List}
-	}
+		return certReqCAsMsg{cas: cas}
+	})
 }
 
 // refreshViewport updates the viewport content and scrolls to show the focused field.
@@ -255,7 +261,7 @@ func (c *CertRequest) Update(msg tea.Msg) tea.Cmd {
 			if isUnauthorized(msg.Err) {
 				return func() tea.Msg { return SessionExpiredMsg{} }
 			}
-			c.err = msg.Err.Error()
+			c.err = errorText(msg.Err)
 		}
 		return nil
 	}
@@ -316,10 +322,10 @@ func (c *CertRequest) submit() tea.Cmd {
 	}
 
 	cmd := c.spinner.Start("Requesting certificate...")
-	return tea.Batch(cmd, func() tea.Msg {
-		cert, err := c.client.RequestSSLCert(context.Background(), req)
+	return tea.Batch(cmd, c.spinner.Run(func(ctx context.Context) tea.Msg {
+		cert, err := c.client.RequestSSLCert(ctx, req)
 		return CertRequestedMsg{Cert: cert, Err: err}
-	})
+	}))
 }
 
 // View renders the cert request form inside a viewport.
@@ -28,31 +28,57 @@ type DashboardStatsMsg DashboardStats
 
 // Dashboard is the main dashboard view.
 type Dashboard struct {
-	client  *api.Client
+	client  api.Service
 	profile *api.UserProfile
+	caps    api.Capabilities
 	stats   DashboardStats
 	spinner components.Spinner
 	width   int
 	height  int
+	// stale is set when loading the stats was cancelled.
+	stale bool
 }
 
 // NewDashboard creates a new dashboard view.
-func NewDashboard(client *api.Client, profile *api.UserProfile) Dashboard {
+func NewDashboard(client api.Service, profile *api.UserProfile, caps api.Capabilities) Dashboard {
 	return Dashboard{
 		client:  client,
 		profile: profile,
+		caps:    caps,
 		spinner: components.NewSpinner(),
 	}
 }
 
+// Cancel aborts the view's running requests and reports whether a
+// spinner operation was interrupted.
+func (d *Dashboard) Cancel() bool {
+	if d.spinner.Cancel() {
+		d.stale = true
+		return true
+	}
+	return false
+}
+
+// Resume reloads the stats if their last load was cancelled.
+func (d *Dashboard) Resume() tea.Cmd {
+	if !d.stale {
+		return nil
+	}
+	return d.Init()
+}
+
 // SetSize updates the view dimensions.
 func (d *Dashboard) SetSize(width, height int) {
 	d.width = width
 	d.height = height
 }
 
-// Init fetches dashboard stats.
+// Init fetches dashboard stats, unless the server has no /count endpoints.
 func (d *Dashboard) Init() tea.Cmd {
+	d.stale = false
+	if !d.caps.Has(api.CapStats) {
+		return nil
+	}
 	cmd := d.spinner.Start("Loading stats...")
 	return tea.Batch(cmd, d.fetchStats())
 }
@@ -62,8 +88,7 @@ func (d *Dashboard) fetchStats() tea.Cmd {
 	if d.profile != nil {
 		role = d.profile.Role
 	}
-	return func() tea.Msg {
-		ctx := context.Background()
+	return d.spinner.Run(func(ctx context.Context) tea.Msg {
 		stats := DashboardStats{}
 
 		// All users: binded CA count and requested SSL count.
@@ -110,7 +135,7 @@ func (d *Dashboard) fetchStats() tea.Cmd {
 		}
 
 		return DashboardStatsMsg(stats)
-	}
+	})
 }
 
 // Update handles messages.
@@ -120,6 +145,11 @@ func (d *Dashboard) Update(msg tea.Msg) tea.Cmd {
 		d.spinner.Stop()
 		d.stats = DashboardStats(msg)
 		return nil
+
+	case tea.KeyMsg:
+		if msg.String() == "r" && !d.spinner.IsActive() {
+			return d.Init()
+		}
 	}
 	return d.spinner.Update(msg)
 }
@@ -132,33 +162,10 @@ func renderStatCard(icon, label string, val int64) string {
 	))
 }
 
-// View renders the dashboard.
-func (d *Dashboard) View() string {
+// statCards renders the stats visible to role.
+func (d *Dashboard) statCards(role int) string {
 	var sb strings.Builder
 
-	sb.WriteString(tui.TitleStyle.Render("📊 Dashboard"))
-	sb.WriteString("\n\n")
-
-	role := 1
-	if d.profile != nil {
-		sb.WriteString(tui.SubtitleStyle.Render(fmt.Sprintf("Welcome, %s!", d.profile.DisplayName)))
-		sb.WriteString("\n")
-		roleStyled := tui.RoleStyle(d.profile.Role).Render(api.RoleName(d.profile.Role))
-		sb.WriteString(tui.NormalStyle.Render("Role: ") + roleStyled + tui.NormalStyle.Render(" | Email: "+d.profile.Email))
-		sb.WriteString("\n\n")
-		role = d.profile.Role
-	}
-
-	if d.spinner.IsActive() {
-		sb.WriteString(d.spinner.View())
-		sb.WriteString("\n")
-		return sb.String()
-	}
-
-	// --- Stats cards ---
-	sb.WriteString(tui.SubtitleStyle.Render("Quick Stats"))
-	sb.WriteString("\n\n")
-
 	// Row 1: visible to all
 	row1 := lipgloss.JoinHorizontal(lipgloss.Top,
 		renderStatCard("🔐", "Binded CA", d.stats.BindedCA),
@@ -189,6 +196,46 @@ func (d *Dashboard) View() string {
 		sb.WriteString(row3)
 		sb.WriteString("\n\n")
 	}
+	return sb.String()
+}
+
+// View renders the dashboard.
+func (d *Dashboard) View() string {
+	var sb strings.Builder
+
+	sb.WriteString(tui.TitleStyle.Render("📊 Dashboard"))
+	sb.WriteString("\n\n")
+
+	role := 1
+	if d.profile != nil {
+		sb.WriteString(tui.SubtitleStyle.Render(fmt.Sprintf("Welcome, %s!", d.profile.DisplayName)))
+		sb.WriteString("\n")
+		roleStyled := tui.RoleStyle(d.profile.Role).Render(api.RoleName(d.profile.Role))
+		sb.WriteString(tui.NormalStyle.Render("Role: ") + roleStyled + tui.NormalStyle.Render(" | Email: "+d.profile.Email))
+		sb.WriteString("\n\n")
+		role = d.profile.Role
+	}
+
+	if d.spinner.IsActive() {
+		sb.WriteString(d.spinner.View())
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	// --- Stats cards ---
+	sb.WriteString(tui.SubtitleStyle.Render("Quick Stats"))
+	sb.WriteString("\n\n")
+	if d.stale {
+		sb.WriteString(tui.MutedStyle.Render("Loading cancelled — press r to reload."))
+		sb.WriteString("\n\n")
+	}
+
+	if err := d.caps.Check(api.CapStats); err != nil {
+		sb.WriteString(tui.MutedStyle.Render(err.Error() + "."))
+		sb.WriteString("\n\n")
+	} else {
+		sb.WriteString(d.statCards(role))
+	}
 
 	// Navigation hints
 	sb.WriteString(tui.SubtitleStyle.Render("Navigation"))
@@ -0,0 +1,119 @@
+package views
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/certfmt"
+	tui "github.com/gregPerlinLi/CertVaultCLIX/internal/tui/styles"
+)
+
+// DebugPanel lists the most recent API requests, with the redacted headers
+// and bodies of the selected one. It is toggled globally from the App.
+type DebugPanel struct {
+	traces   *api.TraceRing
+	selected int // index from the newest request; 0 is the newest
+	width    int
+	height   int
+}
+
+// NewDebugPanel creates a panel showing the requests recorded in traces.
+func NewDebugPanel(traces *api.TraceRing) DebugPanel {
+	return DebugPanel{traces: traces}
+}
+
+// SetSize updates dimensions.
+func (d *DebugPanel) SetSize(width, height int) {
+	d.width = width
+	d.height = height
+}
+
+// Update moves the selection. It returns true when the panel should close.
+func (d *DebugPanel) Update(msg tea.Msg) bool {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return false
+	}
+	switch key.String() {
+	case "esc", "q":
+		return true
+	case "up", "k":
+		if d.selected > 0 {
+			d.selected--
+		}
+	case "down", "j":
+		if d.selected < len(d.traces.List())-1 {
+			d.selected++
+		}
+	case "home", "g":
+		d.selected = 0
+	}
+	return false
+}
+
+// View renders the panel.
+func (d *DebugPanel) View() string {
+	var sb strings.Builder
+	sb.WriteString(tui.TitleStyle.Render("🐞 API Requests"))
+	sb.WriteString("\n\n")
+
+	traces := d.traces.List()
+	if len(traces) == 0 {
+		sb.WriteString(tui.MutedStyle.Render("No requests yet."))
+		sb.WriteString("\n\n")
+		sb.WriteString(tui.HelpStyle.Render("esc: close"))
+		return sb.String()
+	}
+	d.selected = min(d.selected, len(traces)-1)
+
+	width := max(d.width-2, 20)
+	// Newest first; the list takes at most a third of the height.
+	rows := min(len(traces), max(d.height/3, 3))
+	start := max(d.selected-rows+1, 0)
+	for i := start; i < start+rows && i < len(traces); i++ {
+		t := traces[len(traces)-1-i]
+		line := truncate(t.Time.Format("15:04:05")+"  "+t.Summary(), width-2)
+		switch {
+		case i == d.selected:
+			sb.WriteString(tui.SelectedStyle.Render("▶ " + line))
+		case t.Err != "" || t.Status >= 400:
+			sb.WriteString(tui.DangerStyle.Render("  " + line))
+		default:
+			sb.WriteString(tui.NormalStyle.Render("  " + line))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(tui.MutedStyle.Render(fmt.Sprintf("%d of %d recorded requests", d.selected+1, len(traces))))
+	sb.WriteString("\n\n")
+
+	t := traces[len(traces)-1-d.selected]
+	detail := []string{tui.NormalStyle.Render(t.Method + " " + t.URL)}
+	for _, name := range slices.Sorted(maps.Keys(t.Header)) {
+		detail = append(detail, tui.MutedStyle.Render("> "+name+": "+strings.Join(t.Header[name], ", ")))
+	}
+	if t.Header == nil {
+		detail = append(detail, tui.MutedStyle.Render("Sent before this panel was first opened; only the summary was recorded."))
+	}
+	if t.Request != "" {
+		detail = append(detail, "", tui.SubtitleStyle.Render("Request"), certfmt.Wrap(t.Request, width, ""))
+	}
+	if t.Response != "" {
+		detail = append(detail, "", tui.SubtitleStyle.Render("Response"), certfmt.Wrap(t.Response, width, ""))
+	}
+	if t.Err != "" {
+		detail = append(detail, "", tui.DangerStyle.Render(certfmt.Wrap("Error: "+t.Err, width, "  ")))
+	}
+	// Keep the help line visible by cutting the detail to the remaining height.
+	lines := strings.Split(strings.Join(detail, "\n"), "\n")
+	if room := d.height - rows - 7; room > 0 && len(lines) > room {
+		lines = append(lines[:room-1], tui.MutedStyle.Render("…"))
+	}
+	sb.WriteString(strings.Join(lines, "\n"))
+	sb.WriteString("\n\n")
+	sb.WriteString(tui.HelpStyle.Render("↑/↓: select request • esc: close • secrets are redacted"))
+	return sb.String()
+}
@@ -2,8 +2,6 @@ package views
 
 import (
 	"errors"
-	"strings"
-	"time"
 
 	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
 )
@@ -14,61 +12,6 @@ type inlineAnalysisMsg struct {
 	err    string
 }
 
-// wrapText wraps a plain-text string so that each output line is at most maxWidth
-// rune-columns wide. Continuation lines are prefixed with indent.
-func wrapText(text string, maxWidth int, indent string) string {
-	if maxWidth <= 0 {
-		return text
-	}
-	runes := []rune(text)
-	if len(runes) <= maxWidth {
-		return text
-	}
-	indentRunes := []rune(indent)
-	// Continuation lines may be shorter due to indent; ensure minimum usable width.
-	contWidth := maxWidth - len(indentRunes)
-	if contWidth < 10 {
-		contWidth = maxWidth // indent too wide: fall back to maxWidth, no extra indent
-	}
-
-	breakAt := func(r []rune, width int) int {
-		if len(r) <= width {
-			return len(r)
-		}
-		// Search backwards from width-1 for a space to break on a word boundary.
-		w := width - 1
-		for w > 0 && r[w] != ' ' {
-			w--
-		}
-		if w == 0 {
-			return width // hard break: no space found within width
-		}
-		return w
-	}
-
-	var b strings.Builder
-	// First line.
-	cut := breakAt(runes, maxWidth)
-	b.WriteString(string(runes[:cut]))
-	rest := runes[cut:]
-	// Skip leading spaces on the remainder.
-	for len(rest) > 0 && rest[0] == ' ' {
-		rest = rest[1:]
-	}
-
-	for len(rest) > 0 {
-		b.WriteByte('\n')
-		b.WriteString(string(indentRunes))
-		cut = breakAt(rest, contWidth)
-		b.WriteString(string(rest[:cut]))
-		rest = rest[cut:]
-		for len(rest) > 0 && rest[0] == ' ' {
-			rest = rest[1:]
-		}
-	}
-	return b.String()
-}
-
 // SessionExpiredMsg is sent when any API call returns 401 Unauthorized.
 type SessionExpiredMsg struct{}
 
@@ -80,23 +23,39 @@ func isUnauthorized(err error) bool {
 	return err != nil && errors.Is(err, api.ErrUnauthorized)
 }
 
+// errorText describes err for display, explaining the usual cause of an API
+// error class next to the server's own message.
+func errorText(err error) string {
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) {
+		return err.Error()
+	}
+	msg := apiErr.Message()
+	switch {
+	case errors.Is(err, api.ErrForbidden):
+		return "Permission denied: " + msg + " — your role does not allow this action"
+	case errors.Is(err, api.ErrNotFound):
+		return "Not found: " + msg + " — it may have been deleted or you no longer have access"
+	case errors.Is(err, api.ErrConflict):
+		return "Conflict: " + msg + " — it already exists or was changed meanwhile"
+	case errors.Is(err, api.ErrValidation):
+		return "Rejected: " + msg
+	case errors.Is(err, api.ErrServer):
+		return "Server error: " + msg + " — try again later"
+	}
+	return err.Error()
+}
+
 // parseDaysLeft parses a date string and returns the number of days until expiry.
 // Handles multiple date formats used by the CertVault API.
 func parseDaysLeft(notAfter string) int {
-	for _, f := range dateFormats {
-		if t, err := time.Parse(f, notAfter); err == nil {
-			return int(time.Until(t).Hours() / 24)
-		}
-	}
-	return 0
+	return api.DaysLeft(notAfter)
 }
 
 // formatNotAfter parses a date string and returns it formatted as YYYY-MM-DD.
 func formatNotAfter(notAfter string) string {
-	for _, f := range dateFormats {
-		if t, err := time.Parse(f, notAfter); err == nil {
-			return t.Format("2006-01-02")
-		}
+	if t, ok := api.ParseTime(notAfter); ok {
+		return t.Format("2006-01-02")
 	}
 	return notAfter
 }
@@ -104,11 +63,3 @@ func formatNotAfter(notAfter string) string {
 // certAlgos is the ordered list of key algorithms supported when requesting
 // CA and SSL certificates. It is shared by CARequest and CertRequest.
 var certAlgos = []string{"RSA", "EC", "ED25519"}
-
-// dateFormats lists date format strings accepted by the API, in priority order.
-var dateFormats = []string{
-	time.RFC3339,
-	"2006-01-02T15:04:05",
-	"2006-01-02T15:04:05.999",
-	"2006-01-02T15:04:05.999999999",
-}
@@ -8,6 +8,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/certfmt"
 	"github.com/gregPerlinLi/CertVaultCLIX/internal/config"
 	"github.com/gregPerlinLi/CertVaultCLIX/internal/tui/components"
 	tui "github.com/gregPerlinLi/CertVaultCLIX/internal/tui/styles"
@@ -31,6 +32,8 @@ const connRefusedHint = "Please install and start the CertVault server first, th
 // LoginSuccessMsg is sent after a successful login.
 type LoginSuccessMsg struct {
 	Profile *api.UserProfile
+	// Caps are the optional features the server supports.
+	Caps api.Capabilities
 }
 
 // LoginErrorMsg is sent after a failed login.
@@ -40,7 +43,7 @@ type LoginErrorMsg struct {
 
 // Login is the login screen view.
 type Login struct {
-	client     *api.Client
+	client     api.Service
 	cfg        *config.Config
 	usernameIn textinput.Model
 	passwordIn textinput.Model
@@ -57,7 +60,7 @@ type Login struct {
 }
 
 // NewLogin creates a new Login view.
-func NewLogin(client *api.Client, cfg *config.Config) Login {
+func NewLogin(client api.Service, cfg *config.Config) Login {
 	u := textinput.New()
 	u.Placeholder = "Username"
 	u.Focus()
@@ -88,6 +91,13 @@ func NewLogin(client *api.Client, cfg *config.Config) Login {
 	}
 }
 
+// Cancel aborts the view's running requests and reports whether a
+// spinner operation was interrupted.
+func (l *Login) Cancel() bool {
+	l.loading = false
+	return l.spinner.Cancel()
+}
+
 // SetSize updates the view dimensions.
 func (l *Login) SetSize(width, height int) {
 	l.width = width
@@ -112,9 +122,10 @@ func (l *Login) Update(msg tea.Msg) (tea.Cmd, bool) {
 				newURL := l.serverIn.Value()
 				if newURL != "" {
 					l.client.SetBaseURL(newURL)
+					l.client.SetHeaders(nil)
 					if l.cfg != nil {
-						l.cfg.ServerURL = newURL
-						_ = config.Save(l.cfg)
+						l.client.SetHeaders(l.cfg.HeadersFor(newURL))
+						_ = l.cfg.SetServer(newURL)
 					}
 				}
 				l.editingURL = false
@@ -166,7 +177,7 @@ func (l *Login) Update(msg tea.Msg) (tea.Cmd, bool) {
 		l.loading = false
 		l.spinner.Stop()
 		if msg.Err != nil {
-			l.err = msg.Err.Error()
+			l.err = errorText(msg.Err)
 			// Detect connection-refused errors and guide the user to the server.
 			if strings.Contains(strings.ToLower(l.err), "connection refused") ||
 				strings.Contains(strings.ToLower(l.err), "no such host") ||
@@ -230,21 +241,16 @@ func (l *Login) doLogin() tea.Cmd {
 
 	spinCmd := l.spinner.Start("Logging in...")
 
-	return tea.Batch(spinCmd, func() tea.Msg {
-		if err := l.client.Login(context.Background(), username, password); err != nil {
+	return tea.Batch(spinCmd, l.spinner.Run(func(ctx context.Context) tea.Msg {
+		if err := l.client.Login(ctx, username, password); err != nil {
 			return LoginErrorMsg{Err: err}
 		}
-		profile, err := l.client.GetProfile(context.Background())
+		profile, err := l.client.GetProfile(ctx)
 		if err != nil {
 			return LoginErrorMsg{Err: err}
 		}
-		// Save session to config
-		if l.cfg != nil {
-			l.cfg.Session = l.client.GetSession()
-			_ = config.Save(l.cfg)
-		}
-		return LoginSuccessMsg{Profile: profile}
-	})
+		return LoginSuccessMsg{Profile: profile, Caps: l.client.Probe(ctx)}
+	}))
 }
 
 // View renders the login screen.
@@ -279,6 +285,10 @@ func (l *Login) View() string {
 		sb.WriteString(tui.HelpStyle.Render("  [ctrl+u] change"))
 		sb.WriteString("\n\n")
 	}
+	if l.client.InsecureTLS() {
+		sb.WriteString(tui.DangerStyle.Render("⚠ TLS verification disabled (insecure mode)"))
+		sb.WriteString("\n\n")
+	}
 
 	// Username
 	var userStyle lipgloss.Style
@@ -323,7 +333,7 @@ func (l *Login) View() string {
 	// Error
 	if l.err != "" {
 		// Wrap to formWidth so long errors don't widen the centering computation.
-		wrapped := wrapText(l.err, formWidth-2, "  ")
+		wrapped := certfmt.Wrap(l.err, formWidth-2, "  ")
 		for i, line := range strings.Split(wrapped, "\n") {
 			if i == 0 {
 				sb.WriteString(tui.DangerStyle.Render("✗ " + line))
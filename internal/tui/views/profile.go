@@ -18,7 +18,7 @@ type ProfileUpdatedMsg struct {
 
 // Profile is the user profile view.
 type Profile struct {
-	client  *api.Client
+	client  api.UserService
 	profile *api.UserProfile
 	form    components.Form
 	fields  []*components.FormField
@@ -30,7 +30,7 @@ type Profile struct {
 }
 
 // NewProfile creates a new profile view.
-func NewProfile(client *api.Client, profile *api.UserProfile) Profile {
+func NewProfile(client api.UserService, profile *api.UserProfile) Profile {
 	fields := []*components.FormField{
 		{Label: "Display Name", Placeholder: "Display name"},
 		{Label: "Email", Placeholder: "Email address"},
@@ -52,6 +52,12 @@ func NewProfile(client *api.Client, profile *api.UserProfile) Profile {
 	return p
 }
 
+// Cancel aborts the view's running requests and reports whether a
+// spinner operation was interrupted.
+func (p *Profile) Cancel() bool {
+	return p.spinner.Cancel()
+}
+
 // SetSize updates dimensions.
 func (p *Profile) SetSize(width, height int) {
 	p.width = width
@@ -76,7 +82,7 @@ func (p *Profile) Update(msg tea.Msg) tea.Cmd {
 	case ProfileUpdatedMsg:
 		p.spinner.Stop()
 		if msg.Err != nil {
-			p.err = msg.Err.Error()
+			p.err = errorText(msg.Err)
 		} else {
 			return p.toast.Show("Profile updated successfully!", components.ToastSuccess)
 		}
@@ -99,10 +105,10 @@ func (p *Profile) submit() tea.Cmd {
 		NewPassword: p.form.Value(3),
 	}
 	cmd := p.spinner.Start("Updating profile...")
-	return tea.Batch(cmd, func() tea.Msg {
-		err := p.client.UpdateProfile(context.Background(), req)
+	return tea.Batch(cmd, p.spinner.Run(func(ctx context.Context) tea.Msg {
+		err := p.client.UpdateProfile(ctx, req)
 		return ProfileUpdatedMsg{Err: err}
-	})
+	}))
 }
 
 // View renders the profile view.
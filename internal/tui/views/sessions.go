@@ -20,7 +20,7 @@ type SessionsLoadedMsg struct {
 
 // Sessions is the session management view.
 type Sessions struct {
-	client   *api.Client
+	client   api.UserService
 	table    components.Table
 	sessions []api.LoginRecord
 	total    int64
@@ -34,7 +34,7 @@ type Sessions struct {
 }
 
 // NewSessions creates a new sessions view.
-func NewSessions(client *api.Client) Sessions {
+func NewSessions(client api.UserService) Sessions {
 	cols := []components.Column{
 		{Title: "UUID", Width: 36},
 		{Title: "IP Address", Width: 18},
@@ -50,6 +50,12 @@ func NewSessions(client *api.Client) Sessions {
 	}
 }
 
+// Cancel aborts the view's running requests and reports whether a
+// spinner operation was interrupted.
+func (s *Sessions) Cancel() bool {
+	return s.spinner.Cancel()
+}
+
 // SetSize updates dimensions.
 func (s *Sessions) SetSize(width, height int) {
 	s.width = width
@@ -64,13 +70,13 @@ func (s *Sessions) Init() tea.Cmd {
 }
 
 func (s *Sessions) load() tea.Cmd {
-	return func() tea.Msg {
-		sessions, err := s.client.ListUserSessions(context.Background(), s.page, 20)
+	return s.spinner.Run(func(ctx context.Context) tea.Msg {
+		sessions, err := s.client.ListUserSessions(ctx, s.page, 20)
 		if err != nil {
 			return SessionsLoadedMsg{Err: err}
 		}
 		return SessionsLoadedMsg{Sessions: sessions.List, Total: sessions.Total}
-	}
+	})
 }
 
 // Update handles messages.
@@ -82,7 +88,7 @@ func (s *Sessions) Update(msg tea.Msg) tea.Cmd {
 			if isUnauthorized(msg.Err) {
 				return func() tea.Msg { return SessionExpiredMsg{} }
 			}
-			s.err = msg.Err.Error()
+			s.err = errorText(msg.Err)
 			return nil
 		}
 		s.sessions = msg.Sessions
@@ -144,17 +150,17 @@ func (s *Sessions) logoutSelected() tea.Cmd {
 		return nil
 	}
 	uuid := s.sessions[idx].UUID
-	return func() tea.Msg {
-		err := s.client.LogoutSession(context.Background(), uuid)
+	return s.spinner.Run(func(ctx context.Context) tea.Msg {
+		err := s.client.LogoutSession(ctx, uuid)
 		if err != nil {
 			return SessionsLoadedMsg{Err: err}
 		}
-		sessions, err := s.client.ListUserSessions(context.Background(), s.page, 20)
+		sessions, err := s.client.ListUserSessions(ctx, s.page, 20)
 		if err != nil {
 			return SessionsLoadedMsg{Err: err}
 		}
 		return SessionsLoadedMsg{Sessions: sessions.List, Total: sessions.Total}
-	}
+	})
 }
 
 func (s *Sessions) buildRows() []components.Row {
@@ -215,8 +221,9 @@ func (s *Sessions) View() string {
 }
 
 func truncate(s string, n int) string {
-	if len(s) <= n {
+	r := []rune(s)
+	if len(r) <= n {
 		return s
 	}
-	return s[:n-3] + "..."
+	return string(r[:max(n-3, 0)]) + "..."
 }
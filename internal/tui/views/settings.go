@@ -73,8 +73,7 @@ func (s *Settings) Update(msg tea.Msg) (tea.Cmd, bool) {
 			if s.editing {
 				newURL := s.input.Value(0)
 				if newURL != "" && s.cfg != nil {
-					s.cfg.ServerURL = newURL
-					_ = config.Save(s.cfg)
+					_ = s.cfg.SetServer(newURL)
 					s.editing = false
 					cmd := s.toast.Show("Server URL updated!", components.ToastSuccess)
 					return cmd, true
@@ -94,7 +94,8 @@ func saRoleValueToIdx(v int) int {
 
 // Superadmin is the superadmin management view.
 type Superadmin struct {
-	client  *api.Client
+	client  api.Service
+	caps    api.Capabilities
 	mode    SuperadminMode
 	menuIdx int
 	table   components.Table
@@ -142,8 +143,9 @@ type Superadmin struct {
 	height       int
 }
 
-// NewSuperadmin creates a new superadmin view.
-func NewSuperadmin(client *api.Client) Superadmin {
+// NewSuperadmin creates a new superadmin view. The session lists are
+// disabled when caps lacks api.CapSessions.
+func NewSuperadmin(client api.Service, caps api.Capabilities) Superadmin {
 	cols := []components.Column{
 		{Title: "Username", Width: 20},
 		{Title: "IP Address", Width: 18},
@@ -177,6 +179,7 @@ func NewSuperadmin(client *api.Client) Superadmin {
 
 	return Superadmin{
 		client:           client,
+		caps:             caps,
 		table:            components.NewTable(cols, 15),
 		page:             1,
 		userSessionsPage: 1,
@@ -190,6 +193,12 @@ func NewSuperadmin(client *api.Client) Superadmin {
 	}
 }
 
+// Cancel aborts the view's running requests and reports whether a
+// spinner operation was interrupted.
+func (s *Superadmin) Cancel() bool {
+	return s.spinner.Cancel()
+}
+
 // SetSize updates dimensions.
 func (s *Superadmin) SetSize(width, height int) {
 	s.width = width
@@ -210,7 +219,7 @@ func (s *Superadmin) Update(msg tea.Msg) tea.Cmd {
 			if isUnauthorized(msg.Err) {
 				return func() tea.Msg { return SessionExpiredMsg{} }
 			}
-			s.err = msg.Err.Error()
+			s.err = errorText(msg.Err)
 			return nil
 		}
 		s.err = ""
@@ -240,7 +249,7 @@ func (s *Superadmin) Update(msg tea.Msg) tea.Cmd {
 			if isUnauthorized(msg.Err) {
 				return func() tea.Msg { return SessionExpiredMsg{} }
 			}
-			s.err = msg.Err.Error()
+			s.err = errorText(msg.Err)
 			return nil
 		}
 		s.err = ""
@@ -269,7 +278,7 @@ func (s *Superadmin) Update(msg tea.Msg) tea.Cmd {
 			if isUnauthorized(msg.Err) {
 				return func() tea.Msg { return SessionExpiredMsg{} }
 			}
-			s.err = msg.Err.Error()
+			s.err = errorText(msg.Err)
 			return nil
 		}
 		s.err = ""
@@ -319,6 +328,9 @@ func (s *Superadmin) Update(msg tea.Msg) tea.Cmd {
 					s.menuIdx++
 				}
 			case "enter":
+				if s.menuIdx == 0 && !s.caps.Has(api.CapSessions) {
+					return nil
+				}
 				s.err = ""
 				s.page = 1
 				if s.menuIdx == 0 {
@@ -508,7 +520,7 @@ func (s *Superadmin) Update(msg tea.Msg) tea.Cmd {
 						s.dialog = &d
 					}
 				case 4: // View Sessions
-					if s.selectedUser != nil {
+					if s.selectedUser != nil && s.caps.Has(api.CapSessions) {
 						s.userSessionsPage = 1
 						s.table.Columns = []components.Column{
 							{Title: "UUID", Width: 36},
@@ -614,8 +626,7 @@ func (s *Superadmin) Update(msg tea.Msg) tea.Cmd {
 func (s *Superadmin) load() tea.Cmd {
 	mode := s.mode
 	page := s.page
-	return func() tea.Msg {
-		ctx := context.Background()
+	return s.spinner.Run(func(ctx context.Context) tea.Msg {
 		switch mode {
 		case SuperadminModeSessions:
 			sessions, err := s.client.ListAllSessions(ctx, page, 20)
@@ -631,7 +642,7 @@ func (s *Superadmin) load() tea.Cmd {
 			return SuperadminDataMsg{Users: users.List, Total: users.Total}
 		}
 		return SuperadminDataMsg{}
-	}
+	})
 }
 
 func (s *Superadmin) loadUserSessions() tea.Cmd {
@@ -640,13 +651,13 @@ func (s *Superadmin) loadUserSessions() tea.Cmd {
 	}
 	username := s.selectedUser.Username
 	page := s.userSessionsPage
-	return func() tea.Msg {
-		sessions, err := s.client.ListUserSessionsBySuperadmin(context.Background(), username, page, 20)
+	return s.spinner.Run(func(ctx context.Context) tea.Msg {
+		sessions, err := s.client.ListUserSessionsBySuperadmin(ctx, username, page, 20)
 		if err != nil {
 			return SuperadminUserSessionsMsg{Err: err}
 		}
 		return SuperadminUserSessionsMsg{Sessions: sessions.List, Total: sessions.Total}
-	}
+	})
 }
 
 // refreshSelectedUser reloads the user list and looks up the selected user by username.
@@ -656,8 +667,8 @@ func (s *Superadmin) refreshSelectedUser() tea.Cmd {
 		username = s.selectedUser.Username
 	}
 	page := s.page
-	return func() tea.Msg {
-		users, err := s.client.ListAdminUsers(context.Background(), page, 20)
+	return s.spinner.Run(func(ctx context.Context) tea.Msg {
+		users, err := s.client.ListAdminUsers(ctx, page, 20)
 		if err != nil {
 			return SuperadminDataMsg{Err: err}
 		}
@@ -667,7 +678,7 @@ func (s *Superadmin) refreshSelectedUser() tea.Cmd {
 			}
 		}
 		return SuperadminDataMsg{Users: users.List, Total: users.Total}
-	}
+	})
 }
 
 // -- Write operations --------------------------------------------------------
@@ -692,12 +703,11 @@ func (s *Superadmin) forceLogoutByUsername() tea.Cmd {
 	}
 	mode := s.mode
 	page := s.page
-	return func() tea.Msg {
-		err := s.client.ForceLogoutUser(context.Background(), username)
+	return s.spinner.Run(func(ctx context.Context) tea.Msg {
+		err := s.client.ForceLogoutUser(ctx, username)
 		if err != nil {
 			return SuperadminDataMsg{Err: err}
 		}
-		ctx := context.Background()
 		switch mode {
 		case SuperadminModeSessions:
 			sessions, err := s.client.ListAllSessions(ctx, page, 20)
@@ -715,7 +725,7 @@ func (s *Superadmin) forceLogoutByUsername() tea.Cmd {
 			return SuperadminUserOpMsg{}
 		}
 		return SuperadminDataMsg{}
-	}
+	})
 }
 
 func (s *Superadmin) deleteUser() tea.Cmd {
@@ -724,17 +734,17 @@ func (s *Superadmin) deleteUser() tea.Cmd {
 	}
 	username := s.selectedUser.Username
 	page := s.page
-	return func() tea.Msg {
-		err := s.client.DeleteSuperadminUser(context.Background(), username)
+	return s.spinner.Run(func(ctx context.Context) tea.Msg {
+		err := s.client.DeleteSuperadminUser(ctx, username)
 		if err != nil {
 			return SuperadminDataMsg{Err: err}
 		}
-		users, err := s.client.ListAdminUsers(context.Background(), page, 20)
+		users, err := s.client.ListAdminUsers(ctx, page, 20)
 		if err != nil {
 			return SuperadminDataMsg{Err: err}
 		}
 		return SuperadminDataMsg{Users: users.List, Total: users.Total}
-	}
+	})
 }
 
 func (s *Superadmin) submitCreateUser() tea.Cmd {
@@ -756,10 +766,10 @@ func (s *Superadmin) submitCreateUser() tea.Cmd {
 		Role:        role,
 	}
 	cmd := s.spinner.Start("Creating user...")
-	return tea.Batch(cmd, func() tea.Msg {
-		_, err := s.client.CreateUser(context.Background(), req)
+	return tea.Batch(cmd, s.spinner.Run(func(ctx context.Context) tea.Msg {
+		_, err := s.client.CreateUser(ctx, req)
 		return SuperadminUserOpMsg{Err: err}
-	})
+	}))
 }
 
 func (s *Superadmin) submitEditUser() tea.Cmd {
@@ -772,10 +782,10 @@ func (s *Superadmin) submitEditUser() tea.Cmd {
 		Email:       s.editUserForm.Value(1),
 	}
 	cmd := s.spinner.Start("Updating user...")
-	return tea.Batch(cmd, func() tea.Msg {
-		err := s.client.UpdateSuperadminUser(context.Background(), username, req)
+	return tea.Batch(cmd, s.spinner.Run(func(ctx context.Context) tea.Msg {
+		err := s.client.UpdateSuperadminUser(ctx, username, req)
 		return SuperadminUserOpMsg{Err: err}
-	})
+	}))
 }
 
 func (s *Superadmin) submitChangeRole() tea.Cmd {
@@ -786,10 +796,10 @@ func (s *Superadmin) submitChangeRole() tea.Cmd {
 	newRole := saRoleValues[s.roleIdx]
 	req := api.UpdateUserRoleRequest{Username: username, Role: newRole}
 	cmd := s.spinner.Start("Updating role...")
-	return tea.Batch(cmd, func() tea.Msg {
-		err := s.client.UpdateUserRole(context.Background(), req)
+	return tea.Batch(cmd, s.spinner.Run(func(ctx context.Context) tea.Msg {
+		err := s.client.UpdateUserRole(ctx, req)
 		return SuperadminUserOpMsg{Err: err}
-	})
+	}))
 }
 
 func (s *Superadmin) submitChangePassword() tea.Cmd {
@@ -804,10 +814,10 @@ func (s *Superadmin) submitChangePassword() tea.Cmd {
 	username := s.selectedUser.Username
 	req := api.UpdateSuperadminUserRequest{Password: newPassword}
 	cmd := s.spinner.Start("Changing password...")
-	return tea.Batch(cmd, func() tea.Msg {
-		err := s.client.UpdateSuperadminUser(context.Background(), username, req)
+	return tea.Batch(cmd, s.spinner.Run(func(ctx context.Context) tea.Msg {
+		err := s.client.UpdateSuperadminUser(ctx, username, req)
 		return SuperadminUserOpMsg{Err: err}
-	})
+	}))
 }
 
 // -- Row builders ------------------------------------------------------------
@@ -875,14 +885,7 @@ func (s *Superadmin) View() string {
 	switch s.mode {
 
 	case SuperadminModeMenu:
-		for i, item := range superadminMenuItems {
-			if i == s.menuIdx {
-				sb.WriteString(tui.SelectedStyle.Render("> " + item))
-			} else {
-				sb.WriteString(tui.NormalStyle.Render("  " + item))
-			}
-			sb.WriteString("\n")
-		}
+		s.renderMenu(&sb, superadminMenuItems, s.menuIdx, 0)
 		sb.WriteString("\n")
 		sb.WriteString(tui.HelpStyle.Render("up/down: select  enter: open"))
 
@@ -1045,21 +1048,37 @@ func (s *Superadmin) renderUserDetailView(sb *strings.Builder) {
 		}
 		sb.WriteString("\n")
 	}
-	for i, item := range userDetailMenuItems {
-		if i == s.userDetailMenuIdx {
+	s.renderMenu(sb, userDetailMenuItems, s.userDetailMenuIdx, 4)
+	if s.err != "" {
+		sb.WriteString("\n")
+		sb.WriteString(tui.DangerStyle.Render("Error: " + s.err))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(tui.HelpStyle.Render("up/down: select  enter: execute  esc: back to users"))
+}
+
+// renderMenu renders items with selected highlighted. The item at sessions
+// lists sessions; it is dimmed, with the reason shown while it is selected,
+// when the server lacks session management.
+func (s *Superadmin) renderMenu(sb *strings.Builder, items []string, selected, sessions int) {
+	unsupported := s.caps.Check(api.CapSessions)
+	for i, item := range items {
+		switch {
+		case i == selected:
 			sb.WriteString(tui.SelectedStyle.Render("> " + item))
-		} else {
+		case i == sessions && unsupported != nil:
+			sb.WriteString(tui.MutedStyle.Render("  " + item))
+		default:
 			sb.WriteString(tui.NormalStyle.Render("  " + item))
 		}
 		sb.WriteString("\n")
 	}
-	if s.err != "" {
+	if selected == sessions && unsupported != nil {
 		sb.WriteString("\n")
-		sb.WriteString(tui.DangerStyle.Render("Error: " + s.err))
+		sb.WriteString(tui.MutedStyle.Render(unsupported.Error() + "."))
 		sb.WriteString("\n")
 	}
-	sb.WriteString("\n")
-	sb.WriteString(tui.HelpStyle.Render("up/down: select  enter: execute  esc: back to users"))
 }
 
 // renderRoleChangeView renders the role selector.
@@ -3,16 +3,14 @@ package views
 import (
 	"context"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/gregPerlinLi/CertVaultCLIX/internal/api"
+	"github.com/gregPerlinLi/CertVaultCLIX/internal/certfmt"
 	"github.com/gregPerlinLi/CertVaultCLIX/internal/tui/components"
 	tui "github.com/gregPerlinLi/CertVaultCLIX/internal/tui/styles"
 )
@@ -29,7 +27,7 @@ const (
 
 // Tools is the certificate tools view.
 type Tools struct {
-	client       *api.Client
+	client       api.UserService
 	mode         ToolsMode
 	menuIdx      int
 	input        textarea.Model
@@ -51,7 +49,7 @@ var toolsMenuItems = []string{
 }
 
 // NewTools creates a new tools view.
-func NewTools(client *api.Client) Tools {
+func NewTools(client api.UserService) Tools {
 	ta := textarea.New()
 	ta.Placeholder = "Paste PEM content here (e.g. -----BEGIN CERTIFICATE-----)..."
 	ta.SetWidth(60)
@@ -68,6 +66,12 @@ func NewTools(client *api.Client) Tools {
 // toolsInputHeight is the number of lines the input textarea occupies.
 const toolsInputHeight = 6
 
+// Cancel aborts the view's running requests and reports whether a
+// spinner operation was interrupted.
+func (t *Tools) Cancel() bool {
+	return t.spinner.Cancel()
+}
+
 // SetSize updates dimensions.
 func (t *Tools) SetSize(width, height int) {
 	t.width = width
@@ -206,25 +210,24 @@ func (t *Tools) runTool() tea.Cmd {
 	menuIdx := t.menuIdx
 	vpWidth := t.contentWidth
 	cmd := t.spinner.Start("Processing...")
-	return tea.Batch(cmd, func() tea.Msg {
-		ctx := context.Background()
+	return tea.Batch(cmd, t.spinner.Run(func(ctx context.Context) tea.Msg {
 		switch mode {
 		case ToolsModeAnalyzeCert:
 			analysis, err := t.client.AnalyzeCert(ctx, base64.StdEncoding.EncodeToString([]byte(content)))
 			if err != nil {
 				return toolResultMsg{err: err.Error()}
 			}
-			return toolResultMsg{result: formatCertAnalysis(analysis, vpWidth)}
+			return toolResultMsg{result: certfmt.CertAnalysis(analysis, vpWidth)}
 		case ToolsModeAnalyzeKey:
 			analysis, err := t.client.AnalyzePrivKey(ctx, base64.StdEncoding.EncodeToString([]byte(content)), "")
 			if err != nil {
 				return toolResultMsg{err: err.Error()}
 			}
-			return toolResultMsg{result: formatPrivKeyAnalysis(analysis)}
+			return toolResultMsg{result: certfmt.PrivKeyAnalysis(analysis)}
 		case ToolsModeConvert:
 			switch menuIdx {
 			case 2: // PEM to DER
-				result, err := t.client.ConvertPEMtoDER(ctx, content)
+				result, err := t.client.ConvertPEMtoDER(ctx, base64.StdEncoding.EncodeToString([]byte(content)))
 				if err != nil {
 					return toolResultMsg{err: err.Error()}
 				}
@@ -238,119 +241,7 @@ func (t *Tools) runTool() tea.Cmd {
 			}
 		}
 		return toolResultMsg{err: "unknown tool"}
-	})
-}
-
-func formatCertAnalysis(a *api.CertAnalysis, maxWidth int) string {
-	sectionStyle := lipgloss.NewStyle().Foreground(tui.ColorPrimary).Bold(true)
-	keyStyle := lipgloss.NewStyle().Foreground(tui.ColorTextMuted)
-
-	const keyWidth = 19 // "Key:              " including trailing space
-	valueWidth := maxWidth - keyWidth
-	if valueWidth < 20 {
-		valueWidth = 20
-	}
-	indent := strings.Repeat(" ", keyWidth)
-
-	field := func(key, value string) string {
-		k := keyStyle.Render(fmt.Sprintf("%-18s", key+":"))
-		v := wrapText(value, valueWidth, indent)
-		return k + " " + v + "\n"
-	}
-	// expiryField colors only the Not After date.
-	expiryField := func(key, dateStr string) string {
-		k := keyStyle.Render(fmt.Sprintf("%-18s", key+":"))
-		daysLeft := parseDaysLeft(dateStr)
-		v := tui.ExpiryStyle(daysLeft).Render(dateStr)
-		return k + " " + v + "\n"
-	}
-
-	var sb strings.Builder
-	sb.WriteString(sectionStyle.Render("Certificate Analysis"))
-	sb.WriteString("\n\n")
-
-	if a.Subject != "" {
-		sb.WriteString(field("Subject", a.Subject))
-	}
-	if a.Issuer != "" {
-		sb.WriteString(field("Issuer", a.Issuer))
-	}
-	sb.WriteString(field("Not Before", a.NotBefore))
-	sb.WriteString(expiryField("Not After", a.NotAfter))
-	if a.SerialNumber != "" {
-		sb.WriteString(field("Serial Number", a.SerialNumber))
-	}
-	if a.Fingerprint != "" {
-		sb.WriteString(field("Fingerprint", a.Fingerprint))
-	}
-	sb.WriteString(field("Is CA", boolStr(a.IsCA)))
-	sb.WriteString("\n")
-
-	// Public Key section
-	sb.WriteString(sectionStyle.Render("Public Key"))
-	sb.WriteString("\n\n")
-	sb.WriteString(field("Algorithm", a.Algorithm))
-	if len(a.PublicKey) > 0 {
-		keys := make([]string, 0, len(a.PublicKey))
-		for k := range a.PublicKey {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-		for _, k := range keys {
-			v := a.PublicKey[k]
-			var vStr string
-			switch val := v.(type) {
-			case string:
-				vStr = val
-			case float64:
-				vStr = fmt.Sprintf("%.0f", val)
-			case bool:
-				vStr = boolStr(val)
-			default:
-				b, _ := json.Marshal(v)
-				vStr = string(b)
-			}
-			sb.WriteString(field(k, vStr))
-		}
-	}
-	sb.WriteString("\n")
-
-	// Extensions
-	if len(a.Extensions) > 0 {
-		sb.WriteString(sectionStyle.Render("Extensions"))
-		sb.WriteString("\n\n")
-		extKeys := make([]string, 0, len(a.Extensions))
-		for k := range a.Extensions {
-			extKeys = append(extKeys, k)
-		}
-		sort.Strings(extKeys)
-		for _, k := range extKeys {
-			sb.WriteString(field(k, a.Extensions[k]))
-		}
-		sb.WriteString("\n")
-	} else if len(a.SANs) > 0 {
-		sb.WriteString(sectionStyle.Render("Extensions"))
-		sb.WriteString("\n\n")
-		sb.WriteString(field("2.5.29.17", "SAN: "+strings.Join(a.SANs, ", ")))
-		sb.WriteString("\n")
-	}
-
-	return sb.String()
-}
-
-func formatPrivKeyAnalysis(a *api.PrivKeyAnalysis) string {
-	return "Algorithm: " + a.Algorithm + "\nKey Size: " + itoa(a.KeySize) + " bits"
-}
-
-func boolStr(b bool) string {
-	if b {
-		return "Yes"
-	}
-	return "No"
-}
-
-func itoa(i int) string {
-	return fmt.Sprintf("%d", i)
+	}))
 }
 
 // View renders the tools view.